@@ -0,0 +1,268 @@
+package opt
+
+import "cmp"
+
+// The aggregates below follow SQL semantics: None values (i.e. NULL) are ignored,
+// and an aggregate over no Some value is None, except for the counts which are 0.
+//
+// Every aggregate type implements database/sql.Scanner, so that it can be given directly to Rows.Scan while iterating over *sql.Rows:
+// each scanned value is converted like Option.Scan does, then added to the aggregate.
+
+// SumAggregate is a streaming SQL SUM aggregate. Its zero value is ready to use.
+type SumAggregate[N Number] struct {
+	sum   N
+	isSet bool
+}
+
+// Add adds a value to the aggregate. None values are ignored.
+func (a *SumAggregate[N]) Add(v Option[N]) {
+	if v.IsNone() {
+		return
+	}
+	a.sum += v.value
+	a.isSet = true
+}
+
+// Scan adds a value from a database driver to the aggregate.
+// This method is required from database/sql.Scanner interface.
+func (a *SumAggregate[N]) Scan(src any) error {
+	return scanInto(src, a.Add)
+}
+
+// Result returns the sum of the added Some values, or None if there is none.
+func (a *SumAggregate[N]) Result() Option[N] {
+	if !a.isSet {
+		return None[N]()
+	}
+	return Some(a.sum)
+}
+
+// AvgAggregate is a streaming SQL AVG aggregate. Its zero value is ready to use.
+type AvgAggregate[N Number] struct {
+	sum   float64
+	count int
+}
+
+// Add adds a value to the aggregate. None values are ignored.
+func (a *AvgAggregate[N]) Add(v Option[N]) {
+	if v.IsNone() {
+		return
+	}
+	a.sum += float64(v.value)
+	a.count++
+}
+
+// Scan adds a value from a database driver to the aggregate.
+// This method is required from database/sql.Scanner interface.
+func (a *AvgAggregate[N]) Scan(src any) error {
+	return scanInto(src, a.Add)
+}
+
+// Result returns the arithmetic mean of the added Some values, or None if there is none.
+func (a *AvgAggregate[N]) Result() Option[float64] {
+	if a.count == 0 {
+		return None[float64]()
+	}
+	return Some(a.sum / float64(a.count))
+}
+
+// MinAggregate is a streaming SQL MIN aggregate. Its zero value is ready to use.
+type MinAggregate[T cmp.Ordered] struct {
+	min Option[T]
+}
+
+// Add adds a value to the aggregate. None values are ignored.
+func (a *MinAggregate[T]) Add(v Option[T]) {
+	if v.IsSome() && (a.min.IsNone() || cmp.Less(v.value, a.min.value)) {
+		a.min = v
+	}
+}
+
+// Scan adds a value from a database driver to the aggregate.
+// This method is required from database/sql.Scanner interface.
+func (a *MinAggregate[T]) Scan(src any) error {
+	return scanInto(src, a.Add)
+}
+
+// Result returns the smallest of the added Some values, or None if there is none.
+func (a *MinAggregate[T]) Result() Option[T] {
+	return a.min
+}
+
+// MaxAggregate is a streaming SQL MAX aggregate. Its zero value is ready to use.
+type MaxAggregate[T cmp.Ordered] struct {
+	max Option[T]
+}
+
+// Add adds a value to the aggregate. None values are ignored.
+func (a *MaxAggregate[T]) Add(v Option[T]) {
+	if v.IsSome() && (a.max.IsNone() || cmp.Less(a.max.value, v.value)) {
+		a.max = v
+	}
+}
+
+// Scan adds a value from a database driver to the aggregate.
+// This method is required from database/sql.Scanner interface.
+func (a *MaxAggregate[T]) Scan(src any) error {
+	return scanInto(src, a.Add)
+}
+
+// Result returns the greatest of the added Some values, or None if there is none.
+func (a *MaxAggregate[T]) Result() Option[T] {
+	return a.max
+}
+
+// CountAggregate is a streaming SQL COUNT aggregate. Its zero value is ready to use.
+type CountAggregate[T any] struct {
+	count    int
+	countAll int
+}
+
+// Add adds a value to the aggregate.
+func (a *CountAggregate[T]) Add(v Option[T]) {
+	if v.IsSome() {
+		a.count++
+	}
+	a.countAll++
+}
+
+// Scan adds a value from a database driver to the aggregate.
+// This method is required from database/sql.Scanner interface.
+func (a *CountAggregate[T]) Scan(src any) error {
+	return scanInto(src, a.Add)
+}
+
+// Count returns the number of added Some values, like SQL's `COUNT(column)`.
+func (a *CountAggregate[T]) Count() int {
+	return a.count
+}
+
+// CountAll returns the number of added values, None ones included, like SQL's `COUNT(*)`.
+func (a *CountAggregate[T]) CountAll() int {
+	return a.countAll
+}
+
+// FirstAggregate is a streaming aggregate of the first Some value. Its zero value is ready to use.
+type FirstAggregate[T any] struct {
+	first Option[T]
+}
+
+// Add adds a value to the aggregate. None values are ignored.
+func (a *FirstAggregate[T]) Add(v Option[T]) {
+	if a.first.IsNone() {
+		a.first = v
+	}
+}
+
+// Scan adds a value from a database driver to the aggregate.
+// This method is required from database/sql.Scanner interface.
+func (a *FirstAggregate[T]) Scan(src any) error {
+	return scanInto(src, a.Add)
+}
+
+// Result returns the first added Some value, or None if there is none.
+func (a *FirstAggregate[T]) Result() Option[T] {
+	return a.first
+}
+
+// LastAggregate is a streaming aggregate of the last Some value. Its zero value is ready to use.
+type LastAggregate[T any] struct {
+	last Option[T]
+}
+
+// Add adds a value to the aggregate. None values are ignored.
+func (a *LastAggregate[T]) Add(v Option[T]) {
+	if v.IsSome() {
+		a.last = v
+	}
+}
+
+// Scan adds a value from a database driver to the aggregate.
+// This method is required from database/sql.Scanner interface.
+func (a *LastAggregate[T]) Scan(src any) error {
+	return scanInto(src, a.Add)
+}
+
+// Result returns the last added Some value, or None if there is none.
+func (a *LastAggregate[T]) Result() Option[T] {
+	return a.last
+}
+
+func scanInto[T any](src any, add func(Option[T])) error {
+	var v Option[T]
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	add(v)
+	return nil
+}
+
+// Sum returns the sum of the Some values, or None if there is none.
+func Sum[N Number](values []Option[N]) Option[N] {
+	var a SumAggregate[N]
+	for _, v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// Avg returns the arithmetic mean of the Some values, or None if there is none.
+func Avg[N Number](values []Option[N]) Option[float64] {
+	var a AvgAggregate[N]
+	for _, v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// Min returns the smallest of the Some values, or None if there is none.
+func Min[T cmp.Ordered](values []Option[T]) Option[T] {
+	var a MinAggregate[T]
+	for _, v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// Max returns the greatest of the Some values, or None if there is none.
+func Max[T cmp.Ordered](values []Option[T]) Option[T] {
+	var a MaxAggregate[T]
+	for _, v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// Count returns the number of Some values.
+func Count[T any](values []Option[T]) int {
+	var a CountAggregate[T]
+	for _, v := range values {
+		a.Add(v)
+	}
+	return a.Count()
+}
+
+// CountAll returns the number of values, None ones included.
+func CountAll[T any](values []Option[T]) int {
+	return len(values)
+}
+
+// First returns the first Some value, or None if there is none.
+func First[T any](values []Option[T]) Option[T] {
+	for _, v := range values {
+		if v.IsSome() {
+			return v
+		}
+	}
+	return None[T]()
+}
+
+// Last returns the last Some value, or None if there is none.
+func Last[T any](values []Option[T]) Option[T] {
+	for i := len(values) - 1; i >= 0; i-- {
+		if values[i].IsSome() {
+			return values[i]
+		}
+	}
+	return None[T]()
+}
@@ -0,0 +1,82 @@
+//go:build go1.23
+
+package opt
+
+import (
+	"cmp"
+	"iter"
+)
+
+// SumSeq returns the sum of the Some values of the sequence, or None if there is none.
+func SumSeq[N Number](values iter.Seq[Option[N]]) Option[N] {
+	var a SumAggregate[N]
+	for v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// AvgSeq returns the arithmetic mean of the Some values of the sequence, or None if there is none.
+func AvgSeq[N Number](values iter.Seq[Option[N]]) Option[float64] {
+	var a AvgAggregate[N]
+	for v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// MinSeq returns the smallest of the Some values of the sequence, or None if there is none.
+func MinSeq[T cmp.Ordered](values iter.Seq[Option[T]]) Option[T] {
+	var a MinAggregate[T]
+	for v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// MaxSeq returns the greatest of the Some values of the sequence, or None if there is none.
+func MaxSeq[T cmp.Ordered](values iter.Seq[Option[T]]) Option[T] {
+	var a MaxAggregate[T]
+	for v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
+
+// CountSeq returns the number of Some values of the sequence.
+func CountSeq[T any](values iter.Seq[Option[T]]) int {
+	var a CountAggregate[T]
+	for v := range values {
+		a.Add(v)
+	}
+	return a.Count()
+}
+
+// CountAllSeq returns the number of values of the sequence, None ones included.
+func CountAllSeq[T any](values iter.Seq[Option[T]]) int {
+	var a CountAggregate[T]
+	for v := range values {
+		a.Add(v)
+	}
+	return a.CountAll()
+}
+
+// FirstSeq returns the first Some value of the sequence, or None if there is none.
+// This stops iterating over the sequence as soon as a Some value is found.
+func FirstSeq[T any](values iter.Seq[Option[T]]) Option[T] {
+	for v := range values {
+		if v.IsSome() {
+			return v
+		}
+	}
+	return None[T]()
+}
+
+// LastSeq returns the last Some value of the sequence, or None if there is none.
+func LastSeq[T any](values iter.Seq[Option[T]]) Option[T] {
+	var a LastAggregate[T]
+	for v := range values {
+		a.Add(v)
+	}
+	return a.Result()
+}
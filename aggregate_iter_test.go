@@ -0,0 +1,54 @@
+//go:build go1.23
+
+package opt
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateSeq(t *testing.T) {
+	values := slices.Values([]Option[int]{None[int](), Some(3), None[int](), Some(1), Some(2), None[int]()})
+	empty := slices.Values([]Option[int]{None[int](), None[int]()})
+
+	assert.Equal(t, Some(6), SumSeq(values))
+	assert.Equal(t, None[int](), SumSeq(empty))
+
+	assert.Equal(t, Some(2.0), AvgSeq(values))
+	assert.Equal(t, None[float64](), AvgSeq(empty))
+
+	assert.Equal(t, Some(1), MinSeq(values))
+	assert.Equal(t, None[int](), MinSeq(empty))
+
+	assert.Equal(t, Some(3), MaxSeq(values))
+	assert.Equal(t, None[int](), MaxSeq(empty))
+
+	assert.Equal(t, 3, CountSeq(values))
+	assert.Equal(t, 0, CountSeq(empty))
+
+	assert.Equal(t, 6, CountAllSeq(values))
+	assert.Equal(t, 2, CountAllSeq(empty))
+
+	assert.Equal(t, Some(3), FirstSeq(values))
+	assert.Equal(t, None[int](), FirstSeq(empty))
+
+	assert.Equal(t, Some(2), LastSeq(values))
+	assert.Equal(t, None[int](), LastSeq(empty))
+}
+
+func TestFirstSeq_StopsEarly(t *testing.T) {
+	pulled := 0
+	values := func(yield func(Option[string]) bool) {
+		for _, v := range []Option[string]{None[string](), Some("a"), Some("b")} {
+			pulled++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+
+	assert.Equal(t, Some("a"), FirstSeq(values))
+	assert.Equal(t, 2, pulled)
+}
@@ -0,0 +1,95 @@
+package opt
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	values := []Option[int]{None[int](), Some(3), None[int](), Some(1), Some(2), None[int]()}
+	empty := []Option[int]{None[int](), None[int]()}
+
+	assert.Equal(t, Some(6), Sum(values))
+	assert.Equal(t, None[int](), Sum(empty))
+	assert.Equal(t, None[int](), Sum[int](nil))
+	assert.Equal(t, Some(0), Sum([]Option[int]{Some(0)}))
+
+	assert.Equal(t, Some(2.0), Avg(values))
+	assert.Equal(t, Some(2.5), Avg([]Option[int]{Some(2), Some(3)}))
+	assert.Equal(t, None[float64](), Avg(empty))
+
+	assert.Equal(t, Some(1), Min(values))
+	assert.Equal(t, None[int](), Min(empty))
+	assert.Equal(t, Some("a"), Min([]Option[string]{Some("b"), Some("a")}))
+
+	assert.Equal(t, Some(3), Max(values))
+	assert.Equal(t, None[int](), Max(empty))
+	assert.Equal(t, Some(-1.5), Max([]Option[float64]{Some(-2.0), Some(-1.5)}))
+
+	assert.Equal(t, 3, Count(values))
+	assert.Equal(t, 0, Count(empty))
+
+	assert.Equal(t, 6, CountAll(values))
+	assert.Equal(t, 2, CountAll(empty))
+
+	assert.Equal(t, Some(3), First(values))
+	assert.Equal(t, None[int](), First(empty))
+
+	assert.Equal(t, Some(2), Last(values))
+	assert.Equal(t, None[int](), Last(empty))
+}
+
+func TestAggregate_SQLRows(t *testing.T) {
+	tmpfile, err := os.CreateTemp(os.TempDir(), "testdb")
+	assert.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", tmpfile.Name())
+	assert.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	_, err = db.Exec("CREATE TABLE test_table (id INTEGER NOT NULL PRIMARY KEY, amount INTEGER);")
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO test_table(id, amount) VALUES (1, 10), (2, NULL), (3, 30), (4, NULL), (5, 5)")
+	assert.NoError(t, err)
+
+	rows, err := db.Query("SELECT amount, amount, amount, amount, amount, amount, amount FROM test_table ORDER BY id")
+	assert.NoError(t, err)
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		sum   SumAggregate[int64]
+		avg   AvgAggregate[int64]
+		low   MinAggregate[int64]
+		high  MaxAggregate[int64]
+		count CountAggregate[int64]
+		first FirstAggregate[int64]
+		last  LastAggregate[int64]
+	)
+	for rows.Next() {
+		err := rows.Scan(&sum, &avg, &low, &high, &count, &first, &last)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, rows.Err())
+
+	assert.Equal(t, Some[int64](45), sum.Result())
+	assert.Equal(t, Some(15.0), avg.Result())
+	assert.Equal(t, Some[int64](5), low.Result())
+	assert.Equal(t, Some[int64](30), high.Result())
+	assert.Equal(t, 3, count.Count())
+	assert.Equal(t, 5, count.CountAll())
+	assert.Equal(t, Some[int64](10), first.Result())
+	assert.Equal(t, Some[int64](5), last.Result())
+}
+
+func TestAggregate_Scan_Error(t *testing.T) {
+	var sum SumAggregate[int]
+	assert.Error(t, sum.Scan("not a number"))
+	assert.Equal(t, None[int](), sum.Result())
+}
@@ -0,0 +1,363 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Unmapped lists the fields that Convert could not pair between two types, as dotted paths of Go field names, e.g. "Address.Zip".
+type Unmapped struct {
+	// Src holds the fields of the source type that have no destination field, and are thus not copied.
+	Src []string
+	// Dst holds the fields of the destination type that have no source field, and are thus left untouched.
+	Dst []string
+}
+
+// Converter copies values between two types that mirror each other, such as a struct with pointer fields from a generated client
+// and a domain struct with Option fields.
+//
+// Struct fields are paired by the name of their tag (see TagName), then by field name, then case-insensitively by either.
+// Untagged embedded structs are flattened and fields tagged "-" are ignored. Paired fields are converted as follows:
+//
+//   - *T to Option[U] like FromNillable, and Option[T] to *U like UnwrapAsPtr.
+//   - T to Option[U] always gives Some, and Option[T] to U gives the zero value of U for None.
+//   - Options, pointers, slices and maps are converted element by element, and structs field by field.
+//   - Other types must be identical, or of the same basic kind, e.g. a string to a named string type.
+//
+// The conversion plan of every pair of types is computed once and cached.
+type Converter struct {
+	// TagName is the struct tag used for pairing fields. Defaults to "json".
+	TagName string
+}
+
+// Convert copies src into the value pointed by dst with the default Converter.
+func Convert(dst any, src any) error {
+	_, err := Converter{}.Convert(dst, src)
+	return err
+}
+
+// Convert copies src, or the value pointed by src, into the value pointed by dst.
+// This returns the unmapped fields of both types, or an error if the types cannot be converted.
+func (c Converter) Convert(dst any, src any) (Unmapped, error) {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return Unmapped{}, fmt.Errorf("cannot convert into %T, a non-nil pointer is required", dst)
+	}
+	sv := reflect.ValueOf(src)
+	if !sv.IsValid() {
+		return Unmapped{}, fmt.Errorf("cannot convert nil into %T", dst)
+	}
+	if sv.Kind() == reflect.Pointer && isMergeableStruct(dv.Elem().Type()) {
+		if sv.IsNil() {
+			return Unmapped{}, fmt.Errorf("cannot convert nil %T", src)
+		}
+		sv = sv.Elem()
+	}
+
+	plan := c.plan(dv.Elem().Type(), sv.Type())
+	if plan.err != nil {
+		return Unmapped{}, plan.err
+	}
+	plan.convert(dv.Elem(), sv)
+	// The plan is shared by every conversion between the same types.
+	return Unmapped{Src: slices.Clone(plan.unmapped.Src), Dst: slices.Clone(plan.unmapped.Dst)}, nil
+}
+
+func (c Converter) tagName() string {
+	if c.TagName == "" {
+		return "json"
+	}
+	return c.TagName
+}
+
+type convertKey struct {
+	dst, src reflect.Type
+	tag      string
+}
+
+type convertPlan struct {
+	convert  convertFunc
+	unmapped Unmapped
+	err      error
+}
+
+// convertFunc copies src into dst, which must be addressable.
+type convertFunc func(dst, src reflect.Value)
+
+var convertPlans sync.Map
+
+func (c Converter) plan(dt, st reflect.Type) *convertPlan {
+	key := convertKey{dst: dt, src: st, tag: c.tagName()}
+	if p, ok := convertPlans.Load(key); ok {
+		return p.(*convertPlan)
+	}
+
+	b := converterBuilder{tag: key.tag, structs: map[convertKey]*structConverter{}}
+	p := &convertPlan{}
+	var nested *structConverter
+	p.convert, nested, p.err = b.build(dt, st)
+	if nested != nil {
+		p.unmapped = nested.unmapped
+	}
+	actual, _ := convertPlans.LoadOrStore(key, p)
+	return actual.(*convertPlan)
+}
+
+type converterBuilder struct {
+	tag     string
+	structs map[convertKey]*structConverter
+}
+
+type structConverter struct {
+	fields   []fieldConverter
+	unmapped Unmapped
+}
+
+type fieldConverter struct {
+	dst, src []int
+	convert  convertFunc
+}
+
+// build returns the conversion from st to dt, and the struct conversion it ends with, if any, to report its unmapped fields.
+func (b *converterBuilder) build(dt, st reflect.Type) (convertFunc, *structConverter, error) {
+	dOpt, sOpt := IsOptionType(dt), IsOptionType(st)
+
+	switch {
+	case dt == st:
+		return func(dst, src reflect.Value) { dst.Set(src) }, nil, nil
+
+	case dOpt && sOpt:
+		dElem, sElem := optionElemType(dt), optionElemType(st)
+		conv, nested, err := b.build(dElem, sElem)
+		return func(dst, src reflect.Value) {
+			value, ok := src.Interface().(Optional).AnyValue()
+			if !ok {
+				dst.Addr().Interface().(OptionalPtr).Clear()
+				return
+			}
+			setSome(dst, dElem, func(elem reflect.Value) { conv(elem, valueOf(value, sElem)) })
+		}, nested, err
+
+	case dOpt && st.Kind() == reflect.Pointer:
+		dElem := optionElemType(dt)
+		conv, nested, err := b.build(dElem, st.Elem())
+		return func(dst, src reflect.Value) {
+			if src.IsNil() {
+				dst.Addr().Interface().(OptionalPtr).Clear()
+				return
+			}
+			setSome(dst, dElem, func(elem reflect.Value) { conv(elem, src.Elem()) })
+		}, nested, err
+
+	case dt.Kind() == reflect.Pointer && sOpt:
+		sElem := optionElemType(st)
+		conv, nested, err := b.build(dt.Elem(), sElem)
+		return func(dst, src reflect.Value) {
+			value, ok := src.Interface().(Optional).AnyValue()
+			if !ok {
+				dst.SetZero()
+				return
+			}
+			p := reflect.New(dt.Elem())
+			conv(p.Elem(), valueOf(value, sElem))
+			dst.Set(p)
+		}, nested, err
+
+	case dOpt:
+		dElem := optionElemType(dt)
+		conv, nested, err := b.build(dElem, st)
+		return func(dst, src reflect.Value) {
+			setSome(dst, dElem, func(elem reflect.Value) { conv(elem, src) })
+		}, nested, err
+
+	case sOpt:
+		sElem := optionElemType(st)
+		conv, nested, err := b.build(dt, sElem)
+		return func(dst, src reflect.Value) {
+			value, ok := src.Interface().(Optional).AnyValue()
+			if !ok {
+				dst.SetZero()
+				return
+			}
+			conv(dst, valueOf(value, sElem))
+		}, nested, err
+
+	case dt.Kind() == reflect.Pointer && st.Kind() == reflect.Pointer:
+		conv, nested, err := b.build(dt.Elem(), st.Elem())
+		return func(dst, src reflect.Value) {
+			if src.IsNil() {
+				dst.SetZero()
+				return
+			}
+			p := reflect.New(dt.Elem())
+			conv(p.Elem(), src.Elem())
+			dst.Set(p)
+		}, nested, err
+
+	case dt.Kind() == reflect.Slice && st.Kind() == reflect.Slice:
+		conv, nested, err := b.build(dt.Elem(), st.Elem())
+		return func(dst, src reflect.Value) {
+			if src.IsNil() {
+				dst.SetZero()
+				return
+			}
+			s := reflect.MakeSlice(dt, src.Len(), src.Len())
+			for i := 0; i < src.Len(); i++ {
+				conv(s.Index(i), src.Index(i))
+			}
+			dst.Set(s)
+		}, nested, err
+
+	case dt.Kind() == reflect.Map && st.Kind() == reflect.Map:
+		convKey, _, err := b.build(dt.Key(), st.Key())
+		if err != nil {
+			return nil, nil, err
+		}
+		conv, nested, err := b.build(dt.Elem(), st.Elem())
+		return func(dst, src reflect.Value) {
+			if src.IsNil() {
+				dst.SetZero()
+				return
+			}
+			m := reflect.MakeMapWithSize(dt, src.Len())
+			iter := src.MapRange()
+			for iter.Next() {
+				key := reflect.New(dt.Key()).Elem()
+				convKey(key, iter.Key())
+				value := reflect.New(dt.Elem()).Elem()
+				conv(value, iter.Value())
+				m.SetMapIndex(key, value)
+			}
+			dst.Set(m)
+		}, nested, err
+
+	case isMergeableStruct(dt) && isMergeableStruct(st):
+		sc, err := b.buildStruct(dt, st)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(dst, src reflect.Value) {
+			for _, f := range sc.fields {
+				f.convert(dst.FieldByIndex(f.dst), src.FieldByIndex(f.src))
+			}
+		}, sc, nil
+
+	case dt.Kind() == st.Kind() && isBasicKind(dt.Kind()):
+		return func(dst, src reflect.Value) { dst.Set(src.Convert(dt)) }, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("cannot convert %s to %s", st, dt)
+}
+
+func (b *converterBuilder) buildStruct(dt, st reflect.Type) (*structConverter, error) {
+	key := convertKey{dst: dt, src: st, tag: b.tag}
+	if sc, ok := b.structs[key]; ok {
+		return sc, nil
+	}
+	sc := &structConverter{}
+	b.structs[key] = sc
+
+	dFields, sFields := convertFields(dt, b.tag, nil), convertFields(st, b.tag, nil)
+	used := make([]bool, len(sFields))
+	for _, df := range dFields {
+		i := matchConvertField(df, sFields, used)
+		if i < 0 {
+			sc.unmapped.Dst = append(sc.unmapped.Dst, df.name)
+			continue
+		}
+		sf := sFields[i]
+		used[i] = true
+
+		conv, nested, err := b.build(df.typ, sf.typ)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", df.name, err)
+		}
+		sc.fields = append(sc.fields, fieldConverter{dst: df.index, src: sf.index, convert: conv})
+		if nested != nil && nested != sc {
+			for _, name := range nested.unmapped.Src {
+				sc.unmapped.Src = append(sc.unmapped.Src, sf.name+"."+name)
+			}
+			for _, name := range nested.unmapped.Dst {
+				sc.unmapped.Dst = append(sc.unmapped.Dst, df.name+"."+name)
+			}
+		}
+	}
+	for i, sf := range sFields {
+		if !used[i] {
+			sc.unmapped.Src = append(sc.unmapped.Src, sf.name)
+		}
+	}
+	return sc, nil
+}
+
+type convertField struct {
+	key, name string
+	index     []int
+	typ       reflect.Type
+}
+
+// convertFields returns the fields of the struct type t, with the fields of untagged embedded structs flattened.
+func convertFields(t reflect.Type, tagName string, index []int) []convertField {
+	var fields []convertField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, squash, ok := mapFieldName(f, tagName)
+		if !ok {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if squash {
+			fields = append(fields, convertFields(f.Type, tagName, fieldIndex)...)
+			continue
+		}
+		fields = append(fields, convertField{key: key, name: f.Name, index: fieldIndex, typ: f.Type})
+	}
+	return fields
+}
+
+// matchConvertField returns the index of the unused source field paired with the destination field df, or -1.
+func matchConvertField(df convertField, sFields []convertField, used []bool) int {
+	matchers := []func(sf convertField) bool{
+		func(sf convertField) bool { return sf.key == df.key },
+		func(sf convertField) bool { return sf.name == df.name },
+		func(sf convertField) bool {
+			return strings.EqualFold(sf.key, df.key) || strings.EqualFold(sf.name, df.name)
+		},
+	}
+	for _, match := range matchers {
+		for i, sf := range sFields {
+			if !used[i] && match(sf) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func optionElemType(t reflect.Type) reflect.Type {
+	return reflect.Zero(t).Interface().(Optional).ElemType()
+}
+
+// valueOf returns value as a reflect.Value of type t, value being the value of an Option[t].
+func valueOf(value any, t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+	if value != nil {
+		v.Set(reflect.ValueOf(value))
+	}
+	return v
+}
+
+// setSome sets the Option dst to Some with a value of type elemType filled by fill.
+func setSome(dst reflect.Value, elemType reflect.Type, fill func(elem reflect.Value)) {
+	elem := reflect.New(elemType).Elem()
+	fill(elem)
+	// elem is a T, so this cannot fail.
+	_ = dst.Addr().Interface().(OptionalPtr).SetAny(elem.Interface())
+}
+
+func isBasicKind(k reflect.Kind) bool {
+	return (reflect.Bool <= k && k <= reflect.Complex128) || k == reflect.String
+}
@@ -0,0 +1,143 @@
+package opt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type convertStatus string
+
+type apiAddress struct {
+	Street *string `json:"street"`
+	Zip    *string `json:"zip"`
+}
+
+type apiUser struct {
+	ID       *int64              `json:"id"`
+	Name     *string             `json:"name"`
+	Email    *string             `json:"email_address"`
+	Status   *string             `json:"status"`
+	Address  *apiAddress         `json:"address"`
+	Previous []apiAddress        `json:"previous"`
+	Tags     []string            `json:"tags"`
+	Scores   map[string]*float64 `json:"scores"`
+	Internal *string             `json:"internal"`
+}
+
+type domainAddress struct {
+	Street  Option[string]
+	Zip     Option[string]
+	Country Option[string]
+}
+
+type domainUser struct {
+	ID       Option[int64]
+	Name     Option[string]
+	Mail     Option[string] `json:"email_address"`
+	Status   Option[convertStatus]
+	Address  Option[domainAddress]
+	Previous []domainAddress
+	Tags     Option[[]string]
+	Scores   map[string]Option[float64]
+	Version  int
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestConverter_Convert(t *testing.T) {
+	api := apiUser{
+		ID:       ptr[int64](1),
+		Name:     ptr("alice"),
+		Email:    ptr("alice@example.com"),
+		Status:   ptr("active"),
+		Address:  &apiAddress{Street: ptr("Main St")},
+		Previous: []apiAddress{{Zip: ptr("75001")}},
+		Scores:   map[string]*float64{"a": ptr(0.5), "b": nil},
+		Internal: ptr("x"),
+	}
+
+	user := domainUser{Version: 2}
+	unmapped, err := Converter{}.Convert(&user, &api)
+	assert.NoError(t, err)
+	assert.Equal(t, domainUser{
+		ID:       Some[int64](1),
+		Name:     Some("alice"),
+		Mail:     Some("alice@example.com"),
+		Status:   Some[convertStatus]("active"),
+		Address:  Some(domainAddress{Street: Some("Main St")}),
+		Previous: []domainAddress{{Zip: Some("75001")}},
+		Tags:     Some([]string(nil)),
+		Scores:   map[string]Option[float64]{"a": Some(0.5), "b": None[float64]()},
+		Version:  2,
+	}, user)
+	assert.Equal(t, Unmapped{
+		Src: []string{"Internal"},
+		Dst: []string{"Address.Country", "Previous.Country", "Version"},
+	}, unmapped)
+
+	var back apiUser
+	unmapped, err = Converter{}.Convert(&back, user)
+	assert.NoError(t, err)
+	api.Internal = nil
+	assert.Equal(t, api, back)
+	assert.Equal(t, Unmapped{
+		Src: []string{"Address.Country", "Previous.Country", "Version"},
+		Dst: []string{"Internal"},
+	}, unmapped)
+
+	// The returned slices can be changed without affecting the next conversions.
+	unmapped.Src[0] = "changed"
+	unmapped, err = Converter{}.Convert(&back, user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Address.Country", "Previous.Country", "Version"}, unmapped.Src)
+}
+
+func TestConvert_None(t *testing.T) {
+	var user domainUser
+	assert.NoError(t, Convert(&user, apiUser{}))
+	assert.Equal(t, domainUser{Tags: Some([]string(nil))}, user)
+
+	var api apiUser
+	assert.NoError(t, Convert(&api, domainUser{}))
+	assert.Equal(t, apiUser{}, api)
+}
+
+func TestConvert_Errors(t *testing.T) {
+	type src struct {
+		ID *string
+	}
+	type dst struct {
+		ID Option[int]
+	}
+
+	err := Convert(&dst{}, src{})
+	assert.EqualError(t, err, "ID: cannot convert string to int")
+
+	err = Convert(dst{}, src{})
+	assert.EqualError(t, err, "cannot convert into opt.dst, a non-nil pointer is required")
+
+	err = Convert(&dst{}, (*src)(nil))
+	assert.EqualError(t, err, "cannot convert nil *opt.src")
+
+	err = Convert(&dst{}, nil)
+	assert.EqualError(t, err, "cannot convert nil into *opt.dst")
+}
+
+func TestConvert_Recursive(t *testing.T) {
+	type apiNode struct {
+		Name     *string
+		Children []apiNode
+	}
+	type node struct {
+		Name     Option[string]
+		Children []node
+	}
+
+	var n node
+	err := Convert(&n, apiNode{Name: ptr("root"), Children: []apiNode{{Name: ptr("leaf")}}})
+	assert.NoError(t, err)
+	assert.Equal(t, node{Name: Some("root"), Children: []node{{Name: Some("leaf")}}}, n)
+}
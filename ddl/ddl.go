@@ -0,0 +1,282 @@
+// Package ddl generates CREATE TABLE statements from Go structs.
+// Option fields become nullable columns and any other field becomes a NOT NULL column.
+//
+// Columns are named after the `db` tag of the fields, or after their lower-cased field name when untagged, like opt.BindNamed does.
+// The tag accepts the `pk` option to make the column part of the primary key,
+// and the `type=...` option to override the SQL type, e.g. `db:"name,type=VARCHAR(32)"`.
+// Fields tagged `db:"-"` are ignored and embedded structs and pointers to structs are flattened.
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	opt "github.com/shimmerglass/go-optional"
+)
+
+// Column is a column of a Table.
+type Column struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	PrimaryKey bool
+}
+
+// Table is a table definition for a Dialect.
+type Table struct {
+	Dialect opt.Dialect
+	Name    string
+	Columns []Column
+}
+
+// CreateSQL returns the CREATE TABLE statement of the table.
+func (t Table) CreateSQL() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n", quoteIdent(t.Dialect, t.Name))
+
+	var pk []string
+	for i, c := range t.Columns {
+		if i > 0 {
+			b.WriteString(",\n")
+		}
+		b.WriteString("\t")
+		b.WriteString(columnDefinition(t.Dialect, c))
+		if c.PrimaryKey {
+			pk = append(pk, quoteIdent(t.Dialect, c.Name))
+		}
+	}
+	if len(pk) > 0 {
+		fmt.Fprintf(&b, ",\n\tPRIMARY KEY (%s)", strings.Join(pk, ", "))
+	}
+
+	b.WriteString("\n);")
+	return b.String()
+}
+
+func columnDefinition(dialect opt.Dialect, c Column) string {
+	def := quoteIdent(dialect, c.Name) + " " + c.Type
+	if !c.Nullable {
+		def += " NOT NULL"
+	}
+	return def
+}
+
+func quoteIdent(dialect opt.Dialect, name string) string {
+	if dialect == opt.DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// field is a struct field as seen by either reflect or go/types.
+type field struct {
+	name     string
+	tag      string
+	embedded bool
+	exported bool
+}
+
+type columnTag struct {
+	name    string
+	skip    bool
+	pk      bool
+	sqlType string
+}
+
+func parseColumnTag(f field) (columnTag, error) {
+	name, options, _ := strings.Cut(f.tag, ",")
+	tag := columnTag{name: name, skip: name == "-"}
+	if tag.name == "" {
+		tag.name = strings.ToLower(f.name)
+	}
+
+	for options != "" {
+		var option string
+		option, options, _ = strings.Cut(options, ",")
+		switch {
+		case option == "pk":
+			tag.pk = true
+		case strings.HasPrefix(option, "type="):
+			tag.sqlType = strings.TrimPrefix(option, "type=")
+			if strings.Contains(tag.sqlType, "(") && !strings.Contains(tag.sqlType, ")") {
+				// e.g. `type=DECIMAL(10,2)`, whose comma has been taken as an option separator
+				var rest string
+				rest, options, _ = strings.Cut(options, ",")
+				tag.sqlType += "," + rest
+			}
+		default:
+			return tag, fmt.Errorf("field %s: unknown db tag option %q", f.name, option)
+		}
+	}
+	return tag, nil
+}
+
+// kind is the SQL-relevant kind of a Go type.
+type kind int
+
+const (
+	kindBool kind = iota
+	kindInt
+	kindUint
+	kindFloat
+	kindString
+	kindBytes
+	kindTime
+	kindJSON
+	kindArray
+)
+
+// goType is the SQL-relevant description of a Go type.
+type goType struct {
+	kind kind
+	bits int
+	elem *goType
+}
+
+func (g goType) sqlType(dialect opt.Dialect) (string, error) {
+	switch dialect {
+	case opt.DialectSQLite:
+		return g.sqliteType()
+	case opt.DialectPostgres:
+		return g.postgresType()
+	case opt.DialectMySQL:
+		return g.mysqlType()
+	default:
+		return "", fmt.Errorf("unsupported dialect %s", dialect)
+	}
+}
+
+func (g goType) sqliteType() (string, error) {
+	switch g.kind {
+	case kindBool:
+		return "BOOLEAN", nil
+	case kindInt, kindUint:
+		return "INTEGER", nil
+	case kindFloat:
+		return "REAL", nil
+	case kindString, kindJSON:
+		return "TEXT", nil
+	case kindBytes:
+		return "BLOB", nil
+	case kindTime:
+		return "DATETIME", nil
+	default:
+		return "", fmt.Errorf("arrays are not supported by %s", opt.DialectSQLite)
+	}
+}
+
+func (g goType) postgresType() (string, error) {
+	switch g.kind {
+	case kindBool:
+		return "BOOLEAN", nil
+	case kindInt, kindUint:
+		bits := g.bits
+		if g.kind == kindUint {
+			bits *= 2
+		}
+		switch {
+		case bits <= 16:
+			return "SMALLINT", nil
+		case bits <= 32:
+			return "INTEGER", nil
+		case bits <= 64:
+			return "BIGINT", nil
+		default:
+			return "NUMERIC(20)", nil
+		}
+	case kindFloat:
+		if g.bits == 32 {
+			return "REAL", nil
+		}
+		return "DOUBLE PRECISION", nil
+	case kindString:
+		return "TEXT", nil
+	case kindBytes:
+		return "BYTEA", nil
+	case kindTime:
+		return "TIMESTAMPTZ", nil
+	case kindJSON:
+		return "JSONB", nil
+	default:
+		elem, err := g.elem.postgresType()
+		if err != nil {
+			return "", err
+		}
+		return elem + "[]", nil
+	}
+}
+
+func (g goType) mysqlType() (string, error) {
+	switch g.kind {
+	case kindBool:
+		return "BOOLEAN", nil
+	case kindInt, kindUint:
+		var t string
+		switch {
+		case g.bits <= 8:
+			t = "TINYINT"
+		case g.bits <= 16:
+			t = "SMALLINT"
+		case g.bits <= 32:
+			t = "INT"
+		default:
+			t = "BIGINT"
+		}
+		if g.kind == kindUint {
+			t += " UNSIGNED"
+		}
+		return t, nil
+	case kindFloat:
+		if g.bits == 32 {
+			return "FLOAT", nil
+		}
+		return "DOUBLE", nil
+	case kindString:
+		return "VARCHAR(255)", nil
+	case kindBytes:
+		return "BLOB", nil
+	case kindTime:
+		return "DATETIME(6)", nil
+	case kindJSON:
+		return "JSON", nil
+	default:
+		return "", fmt.Errorf("arrays are not supported by %s", opt.DialectMySQL)
+	}
+}
+
+// builder accumulates the columns of a table while walking a struct.
+type builder struct {
+	table Table
+	seen  map[string]bool
+}
+
+func newBuilder(dialect opt.Dialect, name string) *builder {
+	return &builder{
+		table: Table{Dialect: dialect, Name: name},
+		seen:  map[string]bool{},
+	}
+}
+
+func (b *builder) add(f field, tag columnTag, g goType, nullable bool) error {
+	if b.seen[tag.name] {
+		return fmt.Errorf("field %s: duplicate column %q", f.name, tag.name)
+	}
+	b.seen[tag.name] = true
+
+	sqlType := tag.sqlType
+	if sqlType == "" {
+		var err error
+		sqlType, err = g.sqlType(b.table.Dialect)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+
+	b.table.Columns = append(b.table.Columns, Column{
+		Name:       tag.name,
+		Type:       sqlType,
+		Nullable:   nullable,
+		PrimaryKey: tag.pk,
+	})
+	return nil
+}
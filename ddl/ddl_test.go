@@ -0,0 +1,155 @@
+package ddl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	opt "github.com/shimmerglass/go-optional"
+	"github.com/shimmerglass/go-optional/ddl/testdata/models"
+)
+
+func TestCreateTable(t *testing.T) {
+	{
+		stmt, err := CreateTable(opt.DialectSQLite, "users", models.User{})
+		assert.NoError(t, err)
+		assert.Equal(t, `CREATE TABLE "users" (
+	"id" INTEGER NOT NULL,
+	"name" TEXT NOT NULL,
+	"email" TEXT,
+	"age" INTEGER,
+	"score" REAL NOT NULL,
+	"active" BOOLEAN NOT NULL,
+	"avatar" BLOB,
+	"created_at" DATETIME NOT NULL,
+	"settings" TEXT,
+	"balance" DECIMAL(10,2),
+	PRIMARY KEY ("id")
+);`, stmt)
+	}
+
+	{
+		stmt, err := CreateTable(opt.DialectPostgres, "users", &models.User{})
+		assert.NoError(t, err)
+		assert.Equal(t, `CREATE TABLE "users" (
+	"id" BIGINT NOT NULL,
+	"name" TEXT NOT NULL,
+	"email" TEXT,
+	"age" INTEGER,
+	"score" DOUBLE PRECISION NOT NULL,
+	"active" BOOLEAN NOT NULL,
+	"avatar" BYTEA,
+	"created_at" TIMESTAMPTZ NOT NULL,
+	"settings" JSONB,
+	"balance" DECIMAL(10,2),
+	PRIMARY KEY ("id")
+);`, stmt)
+	}
+
+	{
+		stmt, err := CreateTable(opt.DialectMySQL, "users", models.User{})
+		assert.NoError(t, err)
+		assert.Equal(t, "CREATE TABLE `users` (\n"+
+			"\t`id` BIGINT NOT NULL,\n"+
+			"\t`name` VARCHAR(255) NOT NULL,\n"+
+			"\t`email` VARCHAR(255),\n"+
+			"\t`age` INT,\n"+
+			"\t`score` DOUBLE NOT NULL,\n"+
+			"\t`active` BOOLEAN NOT NULL,\n"+
+			"\t`avatar` BLOB,\n"+
+			"\t`created_at` DATETIME(6) NOT NULL,\n"+
+			"\t`settings` JSON,\n"+
+			"\t`balance` DECIMAL(10,2),\n"+
+			"\tPRIMARY KEY (`id`)\n"+
+			");", stmt)
+	}
+
+	{
+		stmt, err := CreateTable(opt.DialectPostgres, "posts", models.Post{})
+		assert.NoError(t, err)
+		assert.Equal(t, `CREATE TABLE "posts" (
+	"id" BIGINT NOT NULL,
+	"tags" TEXT[] NOT NULL,
+	"lang" BIGINT[],
+	PRIMARY KEY ("id")
+);`, stmt)
+	}
+}
+
+func TestCreateTable_UnexportedEmbedded(t *testing.T) {
+	stmt, err := CreateTable(opt.DialectSQLite, "comments", models.Comment{})
+	assert.NoError(t, err)
+	assert.Equal(t, `CREATE TABLE "comments" (
+	"id" INTEGER NOT NULL,
+	"body" TEXT NOT NULL,
+	"created_at" DATETIME NOT NULL,
+	PRIMARY KEY ("id")
+);`, stmt)
+}
+
+func TestCreateTable_EmbeddedPointer(t *testing.T) {
+	stmt, err := CreateTable(opt.DialectSQLite, "replies", models.Reply{})
+	assert.NoError(t, err)
+	assert.Equal(t, `CREATE TABLE "replies" (
+	"id" INTEGER NOT NULL,
+	"body" TEXT NOT NULL,
+	PRIMARY KEY ("id")
+);`, stmt)
+}
+
+func TestCreateTable_Errors(t *testing.T) {
+	_, err := CreateTable(opt.DialectSQLite, "posts", models.Post{})
+	assert.EqualError(t, err, "field Tags: arrays are not supported by sqlite")
+
+	_, err = CreateTable(opt.DialectSQLite, "t", 42)
+	assert.EqualError(t, err, "int is not a struct type")
+
+	_, err = CreateTable(opt.DialectSQLite, "t", struct{ P *string }{})
+	assert.EqualError(t, err, "field P: pointer type *string is not supported, use opt.Option[string] for a nullable column")
+
+	_, err = CreateTable(opt.DialectSQLite, "t", struct{ D models.Decimal }{})
+	assert.EqualError(t, err, "field D: unsupported type models.Decimal, set the column type with the `type=` tag option")
+
+	_, err = CreateTable(opt.DialectSQLite, "t", struct {
+		A int `db:"a"`
+		B int `db:"a"`
+	}{})
+	assert.EqualError(t, err, `field B: duplicate column "a"`)
+
+	_, err = CreateTable(opt.DialectSQLite, "t", struct {
+		A int `db:"a,unique"`
+	}{})
+	assert.EqualError(t, err, `field A: unknown db tag option "unique"`)
+}
+
+func TestFromType_Uints(t *testing.T) {
+	type Uints struct {
+		U8  uint8
+		U16 uint16
+		U32 uint32
+		U64 uint64
+	}
+
+	table, err := FromType(opt.DialectPostgres, "t", reflectTypeOf[Uints]())
+	assert.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "u8", Type: "SMALLINT"},
+		{Name: "u16", Type: "INTEGER"},
+		{Name: "u32", Type: "BIGINT"},
+		{Name: "u64", Type: "NUMERIC(20)"},
+	}, table.Columns)
+
+	table, err = FromType(opt.DialectMySQL, "t", reflectTypeOf[Uints]())
+	assert.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "u8", Type: "TINYINT UNSIGNED"},
+		{Name: "u16", Type: "SMALLINT UNSIGNED"},
+		{Name: "u32", Type: "INT UNSIGNED"},
+		{Name: "u64", Type: "BIGINT UNSIGNED"},
+	}, table.Columns)
+}
+
+func reflectTypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
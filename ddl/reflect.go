@@ -0,0 +1,147 @@
+package ddl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	opt "github.com/shimmerglass/go-optional"
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// CreateTable returns the CREATE TABLE statement of the table named name whose columns are the fields of v, a struct or a pointer to a struct.
+func CreateTable(dialect opt.Dialect, name string, v any) (string, error) {
+	t, err := FromType(dialect, name, reflect.TypeOf(v))
+	if err != nil {
+		return "", err
+	}
+	return t.CreateSQL(), nil
+}
+
+// FromType returns the definition of the table named name whose columns are the fields of typ, a struct type or a pointer to a struct type.
+func FromType(dialect opt.Dialect, name string, typ reflect.Type) (Table, error) {
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return Table{}, fmt.Errorf("%v is not a struct type", typ)
+	}
+
+	b := newBuilder(dialect, name)
+	if err := b.addReflectFields(typ); err != nil {
+		return Table{}, err
+	}
+	return b.table, nil
+}
+
+func (b *builder) addReflectFields(typ reflect.Type) error {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		f := field{name: sf.Name, tag: sf.Tag.Get("db"), embedded: sf.Anonymous, exported: sf.IsExported()}
+		// The exported fields of embedded structs are promoted even if their type is unexported.
+		if !f.exported && !f.embedded {
+			continue
+		}
+		tag, err := parseColumnTag(f)
+		if err != nil {
+			return err
+		}
+		if tag.skip {
+			continue
+		}
+
+		ft := sf.Type
+		if f.embedded && f.tag == "" {
+			// Embedded pointers to structs are flattened too, like opt.BindNamed does.
+			et := ft
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct && optName(et) == "" {
+				if err := b.addReflectFields(et); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !f.exported {
+			continue
+		}
+
+		nullable := false
+		switch optName(ft) {
+		case "Option":
+			ft = unwrapType(ft)
+			nullable = true
+		case "JSON":
+			nullable = true
+		}
+
+		g, err := reflectGoType(ft)
+		if err != nil && tag.sqlType == "" {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+		if err := b.add(f, tag, g, nullable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optName returns the name of the generic type of the opt package t is an instantiation of, or "" if it's not one.
+func optName(t reflect.Type) string {
+	if t.PkgPath() != opttypes.PkgPath {
+		return ""
+	}
+	name, _, _ := strings.Cut(t.Name(), "[")
+	return name
+}
+
+// unwrapType returns T of an opt.Option[T] type.
+func unwrapType(t reflect.Type) reflect.Type {
+	m, _ := t.MethodByName("Unwrap")
+	return m.Type.Out(0)
+}
+
+func reflectGoType(t reflect.Type) (goType, error) {
+	switch optName(t) {
+	case "Option":
+		return goType{}, fmt.Errorf("nested option type %s is not supported", t)
+	case "JSON":
+		return goType{kind: kindJSON}, nil
+	case "PGArray":
+		elem, err := reflectGoType(unwrapType(t.Elem()))
+		if err != nil {
+			return goType{}, err
+		}
+		return goType{kind: kindArray, elem: &elem}, nil
+	}
+
+	if t == timeType {
+		return goType{kind: kindTime}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return goType{kind: kindBool}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return goType{kind: kindInt, bits: t.Bits()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return goType{kind: kindUint, bits: t.Bits()}, nil
+	case reflect.Float32, reflect.Float64:
+		return goType{kind: kindFloat, bits: t.Bits()}, nil
+	case reflect.String:
+		return goType{kind: kindString}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return goType{kind: kindBytes}, nil
+		}
+	case reflect.Pointer:
+		return goType{}, fmt.Errorf("pointer type %s is not supported, use opt.Option[%s] for a nullable column", t, t.Elem())
+	}
+	return goType{}, fmt.Errorf("unsupported type %s, set the column type with the `type=` tag option", t)
+}
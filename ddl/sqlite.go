@@ -0,0 +1,110 @@
+package ddl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	opt "github.com/shimmerglass/go-optional"
+)
+
+// DiffSQLite compares the table with the schema of an existing SQLite database, as read through `PRAGMA table_info`,
+// and returns the statements that bring the database schema in line with the table.
+//
+// A missing table is created and missing or extra columns are added or dropped.
+// Changes SQLite can't apply with ALTER TABLE, such as a type, nullability or primary key change, or the addition of a NOT NULL column,
+// are returned as SQL comments since they require rebuilding the table.
+func DiffSQLite(ctx context.Context, db *sql.DB, t Table) ([]string, error) {
+	if t.Dialect != opt.DialectSQLite {
+		return nil, fmt.Errorf("cannot diff a %s table against a %s database", t.Dialect, opt.DialectSQLite)
+	}
+
+	existing, err := sqliteColumns(ctx, db, t.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return []string{t.CreateSQL()}, nil
+	}
+
+	table := quoteIdent(t.Dialect, t.Name)
+	byName := map[string]Column{}
+	for _, c := range existing {
+		byName[strings.ToLower(c.Name)] = c
+	}
+
+	var stmts, rebuilds []string
+	for _, c := range t.Columns {
+		name := strings.ToLower(c.Name)
+		current, ok := byName[name]
+		delete(byName, name)
+
+		if !ok {
+			add := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, columnDefinition(t.Dialect, c))
+			if c.PrimaryKey || !c.Nullable {
+				rebuilds = append(rebuilds, fmt.Sprintf("-- %s: a NOT NULL or PRIMARY KEY column can't be added without a default value: %s", c.Name, add))
+				continue
+			}
+			stmts = append(stmts, add)
+			continue
+		}
+
+		var changes []string
+		if !strings.EqualFold(current.Type, c.Type) {
+			changes = append(changes, fmt.Sprintf("type %s -> %s", current.Type, c.Type))
+		}
+		if current.Nullable != c.Nullable {
+			changes = append(changes, fmt.Sprintf("%s -> %s", nullability(current), nullability(c)))
+		}
+		if current.PrimaryKey != c.PrimaryKey {
+			changes = append(changes, fmt.Sprintf("primary key %t -> %t", current.PrimaryKey, c.PrimaryKey))
+		}
+		if len(changes) > 0 {
+			rebuilds = append(rebuilds, fmt.Sprintf("-- %s: %s requires rebuilding the table", c.Name, strings.Join(changes, ", ")))
+		}
+	}
+
+	for _, c := range existing {
+		if _, ok := byName[strings.ToLower(c.Name)]; ok {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, quoteIdent(t.Dialect, c.Name)))
+		}
+	}
+
+	return append(stmts, rebuilds...), nil
+}
+
+func nullability(c Column) string {
+	if c.Nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+func sqliteColumns(ctx context.Context, db *sql.DB, table string) ([]Column, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(opt.DialectSQLite, table)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var columns []Column
+	for rows.Next() {
+		var (
+			cid     int
+			c       Column
+			notNull bool
+			dflt    opt.Option[string]
+			pk      int
+		)
+		if err := rows.Scan(&cid, &c.Name, &c.Type, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		c.Nullable = !notNull
+		c.PrimaryKey = pk > 0
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
@@ -0,0 +1,68 @@
+package ddl
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	opt "github.com/shimmerglass/go-optional"
+	"github.com/shimmerglass/go-optional/ddl/testdata/models"
+)
+
+func TestDiffSQLite(t *testing.T) {
+	tmpfile, err := os.CreateTemp(os.TempDir(), "testdb")
+	assert.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", tmpfile.Name())
+	assert.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	ctx := context.Background()
+	table, err := FromType(opt.DialectSQLite, "users", reflectTypeOf[models.User]())
+	assert.NoError(t, err)
+
+	stmts, err := DiffSQLite(ctx, db, table)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{table.CreateSQL()}, stmts)
+
+	_, err = db.Exec(`CREATE TABLE "users" (
+		"id" INTEGER NOT NULL PRIMARY KEY,
+		"name" TEXT,
+		"email" TEXT,
+		"age" TEXT,
+		"score" REAL NOT NULL,
+		"legacy" TEXT,
+		"created_at" DATETIME NOT NULL,
+		"settings" TEXT,
+		"balance" DECIMAL(10,2)
+	)`)
+	assert.NoError(t, err)
+
+	stmts, err = DiffSQLite(ctx, db, table)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "users" ADD COLUMN "avatar" BLOB;`,
+		`ALTER TABLE "users" DROP COLUMN "legacy";`,
+		`-- name: NULL -> NOT NULL requires rebuilding the table`,
+		`-- age: type TEXT -> INTEGER requires rebuilding the table`,
+		`-- active: a NOT NULL or PRIMARY KEY column can't be added without a default value: ALTER TABLE "users" ADD COLUMN "active" BOOLEAN NOT NULL;`,
+	}, stmts)
+
+	for _, stmt := range stmts[:2] {
+		_, err = db.Exec(stmt)
+		assert.NoError(t, err)
+	}
+	stmts, err = DiffSQLite(ctx, db, table)
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 3)
+
+	table.Dialect = opt.DialectPostgres
+	_, err = DiffSQLite(ctx, db, table)
+	assert.EqualError(t, err, "cannot diff a postgres table against a sqlite database")
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	o "github.com/shimmerglass/go-optional"
+)
+
+type Base struct {
+	ID int64 `db:"id,pk"`
+}
+
+type User struct {
+	Base
+	Name      string            `db:"name"`
+	Email     o.Option[string]  `db:"email"`
+	Age       o.Option[int32]   `db:"age"`
+	Score     float64           `db:"score"`
+	Active    bool              `db:"active"`
+	Avatar    o.Option[[]byte]  `db:"avatar"`
+	CreatedAt time.Time         `db:"created_at"`
+	Settings  o.JSON[Settings]  `db:"settings"`
+	Balance   o.Option[Decimal] `db:"balance,type=DECIMAL(10,2)"`
+	Internal  string            `db:"-"`
+	secret    string
+}
+
+type Settings struct {
+	Theme string
+}
+
+type Decimal struct {
+	Units int64
+}
+
+type Post struct {
+	ID   uint32                   `db:"id,pk"`
+	Tags o.PGArray[string]        `db:"tags"`
+	Lang o.Option[o.PGArray[int]] `db:"lang"`
+}
+
+type timestamps struct {
+	CreatedAt time.Time `db:"created_at"`
+	deletedAt time.Time
+}
+
+type Comment struct {
+	ID   int64  `db:"id,pk"`
+	Body string `db:"body"`
+	timestamps
+}
+
+type Reply struct {
+	*Base
+	Body string `db:"body"`
+}
@@ -0,0 +1,139 @@
+package ddl
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+
+	opt "github.com/shimmerglass/go-optional"
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+)
+
+// FromTypesStruct returns the definition of the table named name whose columns are the fields of st, as type-checked by go/types.
+// This is the static counterpart of FromType, used to generate statements from source code.
+func FromTypesStruct(dialect opt.Dialect, name string, st *types.Struct) (Table, error) {
+	b := newBuilder(dialect, name)
+	if err := b.addTypesFields(st); err != nil {
+		return Table{}, err
+	}
+	return b.table, nil
+}
+
+func (b *builder) addTypesFields(st *types.Struct) error {
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		f := field{name: v.Name(), tag: reflect.StructTag(st.Tag(i)).Get("db"), embedded: v.Embedded(), exported: v.Exported()}
+		// The exported fields of embedded structs are promoted even if their type is unexported.
+		if !f.exported && !f.embedded {
+			continue
+		}
+		tag, err := parseColumnTag(f)
+		if err != nil {
+			return err
+		}
+		if tag.skip {
+			continue
+		}
+
+		ft := v.Type()
+		if f.embedded && f.tag == "" {
+			// Embedded pointers to structs are flattened too, like opt.BindNamed does.
+			et := ft
+			if p, ok := et.(*types.Pointer); ok {
+				et = p.Elem()
+			}
+			if embedded, ok := et.Underlying().(*types.Struct); ok && !isOptType(et) {
+				if err := b.addTypesFields(embedded); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !f.exported {
+			continue
+		}
+
+		nullable := false
+		if elem, ok := opttypes.OptionElem(ft); ok {
+			ft = elem
+			nullable = true
+		} else if _, ok := opttypes.Named(ft, "JSON"); ok {
+			nullable = true
+		}
+
+		g, err := typesGoType(ft)
+		if err != nil && tag.sqlType == "" {
+			return fmt.Errorf("field %s: %w", f.name, err)
+		}
+		if err := b.add(f, tag, g, nullable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isOptType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == opttypes.PkgPath
+}
+
+func typesGoType(t types.Type) (goType, error) {
+	if _, ok := opttypes.OptionElem(t); ok {
+		return goType{}, fmt.Errorf("nested option type %s is not supported", t)
+	}
+	if _, ok := opttypes.Named(t, "JSON"); ok {
+		return goType{kind: kindJSON}, nil
+	}
+	if args, ok := opttypes.Named(t, "PGArray"); ok {
+		elem, err := typesGoType(args[0])
+		if err != nil {
+			return goType{}, err
+		}
+		return goType{kind: kindArray, elem: &elem}, nil
+	}
+
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return goType{kind: kindTime}, nil
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch u.Kind() {
+		case types.Bool:
+			return goType{kind: kindBool}, nil
+		case types.Int, types.Int64:
+			return goType{kind: kindInt, bits: 64}, nil
+		case types.Int8:
+			return goType{kind: kindInt, bits: 8}, nil
+		case types.Int16:
+			return goType{kind: kindInt, bits: 16}, nil
+		case types.Int32:
+			return goType{kind: kindInt, bits: 32}, nil
+		case types.Uint, types.Uint64, types.Uintptr:
+			return goType{kind: kindUint, bits: 64}, nil
+		case types.Uint8:
+			return goType{kind: kindUint, bits: 8}, nil
+		case types.Uint16:
+			return goType{kind: kindUint, bits: 16}, nil
+		case types.Uint32:
+			return goType{kind: kindUint, bits: 32}, nil
+		case types.Float32:
+			return goType{kind: kindFloat, bits: 32}, nil
+		case types.Float64:
+			return goType{kind: kindFloat, bits: 64}, nil
+		case types.String:
+			return goType{kind: kindString}, nil
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			return goType{kind: kindBytes}, nil
+		}
+	case *types.Pointer:
+		return goType{}, fmt.Errorf("pointer type %s is not supported, use opt.Option[%s] for a nullable column", t, u.Elem())
+	}
+	return goType{}, fmt.Errorf("unsupported type %s, set the column type with the `type=` tag option", t)
+}
@@ -0,0 +1,50 @@
+package ddl
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	opt "github.com/shimmerglass/go-optional"
+	"github.com/shimmerglass/go-optional/ddl/testdata/models"
+)
+
+func TestFromTypesStruct(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "testdata/models/models.go", nil, 0)
+	assert.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("models", fset, []*ast.File{f}, nil)
+	assert.NoError(t, err)
+	scope := pkg.Scope()
+
+	for _, tc := range []struct {
+		name    string
+		dialect opt.Dialect
+		typ     any
+	}{
+		{"User", opt.DialectSQLite, models.User{}},
+		{"User", opt.DialectPostgres, models.User{}},
+		{"User", opt.DialectMySQL, models.User{}},
+		{"Post", opt.DialectPostgres, models.Post{}},
+		{"Comment", opt.DialectSQLite, models.Comment{}},
+		{"Reply", opt.DialectSQLite, models.Reply{}},
+	} {
+		st := scope.Lookup(tc.name).Type().Underlying().(*types.Struct)
+		static, err := FromTypesStruct(tc.dialect, "t", st)
+		assert.NoError(t, err)
+
+		dynamic, err := CreateTable(tc.dialect, "t", tc.typ)
+		assert.NoError(t, err)
+		assert.Equal(t, dynamic, static.CreateSQL(), "%s %s", tc.name, tc.dialect)
+	}
+
+	st := scope.Lookup("Post").Type().Underlying().(*types.Struct)
+	_, err = FromTypesStruct(opt.DialectMySQL, "t", st)
+	assert.EqualError(t, err, "field Tags: arrays are not supported by mysql")
+}
@@ -0,0 +1,115 @@
+package opt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ApplyDefaults sets every None Option field of the struct pointed by ptr that has a `default:"..."` tag to Some with the parsed tag value.
+// Tag values are parsed like LoadEnv does, with the `defaultSeparator` tag (default ",") separating slice elements.
+// Nested structs, Some values of Options holding a struct, pointers to structs and slices and arrays of structs are walked recursively.
+// This returns every parse error joined together, each one prefixed with the path of its field.
+func ApplyDefaults(ptr any) error {
+	_, err := ApplyDefaultsWithReport(ptr)
+	return err
+}
+
+// ApplyDefaultsWithReport does the same as ApplyDefaults, and also returns the paths of the defaulted fields, e.g. "servers[0].port".
+// Paths are made of the names of the `yaml` or `json` tags of the fields, or of their field names, like the ones of Validate.
+func ApplyDefaultsWithReport(ptr any) ([]string, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot apply defaults to %T, a pointer to a struct is required", ptr)
+	}
+
+	d := defaulter{}
+	d.walk(v.Elem(), "")
+	return d.applied, errors.Join(d.errs...)
+}
+
+type defaulter struct {
+	applied []string
+	errs    []error
+}
+
+func (d *defaulter) walk(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			d.walk(v.Elem(), path)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			d.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Struct:
+		if o, ok := asOptionPtr(v); ok {
+			d.walkOption(o, path)
+			return
+		}
+		if isLeafStruct(v) {
+			return
+		}
+
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fieldPath := configFieldName(f)
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			fv := v.Field(i)
+
+			if o, ok := asOptionPtr(fv); ok && !o.IsSome() {
+				if tag, ok := f.Tag.Lookup("default"); ok {
+					d.apply(o, tag, f.Tag, fieldPath)
+				}
+				continue
+			}
+			d.walk(fv, fieldPath)
+		}
+	}
+}
+
+// walkOption walks the value of a Some Option, which is copied out and back since it's not addressable.
+func (d *defaulter) walkOption(o OptionalPtr, path string) {
+	value, ok := o.AnyValue()
+	if !ok || value == nil {
+		return
+	}
+	inner := reflect.New(o.ElemType()).Elem()
+	inner.Set(reflect.ValueOf(value))
+
+	before := len(d.applied)
+	d.walk(inner, path)
+	if len(d.applied) > before {
+		// inner is a T, so this cannot fail.
+		_ = o.SetAny(inner.Interface())
+	}
+}
+
+func (d *defaulter) apply(o OptionalPtr, tag string, st reflect.StructTag, path string) {
+	sep, ok := st.Lookup("defaultSeparator")
+	if !ok {
+		sep = ","
+	}
+
+	v, err := parseText(tag, o.ElemType(), sep)
+	if err != nil {
+		d.errs = append(d.errs, fmt.Errorf("%s: %w", path, err))
+		return
+	}
+	if err := o.SetAny(v.Interface()); err != nil {
+		d.errs = append(d.errs, fmt.Errorf("%s: %w", path, err))
+		return
+	}
+	d.applied = append(d.applied, path)
+}
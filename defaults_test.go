@@ -0,0 +1,81 @@
+package opt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type defaultsServer struct {
+	Host Option[string] `json:"host" default:"localhost"`
+	Port Option[int]    `default:"8080"`
+}
+
+type defaultsConfig struct {
+	Timeout  Option[time.Duration] `default:"5s"`
+	Retries  Option[int]           `default:"3"`
+	Tags     Option[[]string]      `default:"a|b" defaultSeparator:"|"`
+	Name     Option[string]
+	Server   defaultsServer
+	Backup   *defaultsServer
+	Servers  []defaultsServer `yaml:"servers"`
+	TLS      Option[defaultsServer]
+	Disabled Option[defaultsServer]
+}
+
+func TestApplyDefaults(t *testing.T) {
+	cfg := defaultsConfig{
+		Retries: Some(0),
+		Server:  defaultsServer{Port: Some(9090)},
+		Backup:  &defaultsServer{},
+		Servers: []defaultsServer{{Host: Some("a")}, {}},
+		TLS:     Some(defaultsServer{Host: Some("tls")}),
+	}
+
+	defaulted, err := ApplyDefaultsWithReport(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultsConfig{
+		Timeout:  Some(5 * time.Second),
+		Retries:  Some(0),
+		Tags:     Some([]string{"a", "b"}),
+		Name:     None[string](),
+		Server:   defaultsServer{Host: Some("localhost"), Port: Some(9090)},
+		Backup:   &defaultsServer{Host: Some("localhost"), Port: Some(8080)},
+		Servers:  []defaultsServer{{Host: Some("a"), Port: Some(8080)}, {Host: Some("localhost"), Port: Some(8080)}},
+		TLS:      Some(defaultsServer{Host: Some("tls"), Port: Some(8080)}),
+		Disabled: None[defaultsServer](),
+	}, cfg)
+	assert.Equal(t, []string{
+		"Timeout",
+		"Tags",
+		"Server.host",
+		"Backup.host",
+		"Backup.Port",
+		"servers[0].Port",
+		"servers[1].host",
+		"servers[1].Port",
+		"TLS.Port",
+	}, defaulted)
+
+	defaulted, err = ApplyDefaultsWithReport(&cfg)
+	assert.NoError(t, err)
+	assert.Empty(t, defaulted)
+}
+
+func TestApplyDefaults_Errors(t *testing.T) {
+	type Invalid struct {
+		Port    Option[int]           `json:"port" default:"http"`
+		Timeout Option[time.Duration] `default:"soon"`
+		Valid   Option[bool]          `default:"true"`
+	}
+
+	var v Invalid
+	err := ApplyDefaults(&v)
+	assert.EqualError(t, err, `port: strconv.ParseInt: parsing "http": invalid syntax
+Timeout: time: invalid duration "soon"`)
+	assert.Equal(t, Some(true), v.Valid)
+
+	err = ApplyDefaults(v)
+	assert.EqualError(t, err, "cannot apply defaults to opt.Invalid, a pointer to a struct is required")
+}
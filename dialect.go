@@ -0,0 +1,36 @@
+package opt
+
+import "strconv"
+
+// Dialect identifies the SQL flavour a query or a statement is written for.
+type Dialect int
+
+const (
+	// DialectSQLite is the SQLite dialect. It uses `?` placeholders.
+	DialectSQLite Dialect = iota
+	// DialectPostgres is the PostgreSQL dialect. It uses `$1`, `$2`, ... placeholders.
+	DialectPostgres
+	// DialectMySQL is the MySQL/MariaDB dialect. It uses `?` placeholders.
+	DialectMySQL
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectSQLite:
+		return "sqlite"
+	case DialectPostgres:
+		return "postgres"
+	case DialectMySQL:
+		return "mysql"
+	default:
+		return "Dialect(" + strconv.Itoa(int(d)) + ")"
+	}
+}
+
+// placeholder returns the bind placeholder of the n-th (1-based) positional argument.
+func (d Dialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
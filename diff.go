@@ -0,0 +1,168 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Change describes a field whose value differs between two structs.
+// For an Option field, Old and New are the contained values, or nil for None.
+type Change struct {
+	Old any
+	New any
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%v -> %v", c.Old, c.New)
+}
+
+// Differ compares two values of the same struct type field by field.
+//
+// Fields are named after their `json` tag, or their field name if untagged, so that paths such as "address.city" can be
+// used to build a FieldMask. Untagged embedded structs are flattened. Nested structs, including Some values of Option fields
+// holding a struct, are compared recursively, and other fields are compared as a whole:
+//
+//   - with their `Equal(T) bool` method if they have one, like time.Time,
+//   - with == if their value is comparable. This is the case of Option[T] when T is comparable,
+//   - with the Equal function of the Differ otherwise.
+type Differ struct {
+	// Equal compares two values of a non-comparable type, such as slices or maps. Defaults to reflect.DeepEqual.
+	Equal func(a, b any) bool
+}
+
+// Diff returns a patch of the changes from old to new with the default Differ.
+// Each changed Option field of the patch is set to its new value, i.e. Some(newValue), and unchanged fields are None.
+// Since a field changed to None is None in the patch too, use Changes to find the cleared fields.
+func Diff[T any](old, new T) (T, error) {
+	var patch T
+	err := Differ{}.Patch(&patch, old, new)
+	return patch, err
+}
+
+// Changes returns the changes from old to new with the default Differ, keyed by field path.
+func Changes(old, new any) (map[string]Change, error) {
+	return Differ{}.Changes(old, new)
+}
+
+// Patch sets the changed fields of the struct pointed by patch to their value in new, and leaves the unchanged fields to their
+// zero value. old, new and the value pointed by patch must be of the same struct type.
+func (d Differ) Patch(patch any, old, new any) error {
+	pv := reflect.ValueOf(patch)
+	if pv.Kind() != reflect.Pointer || pv.IsNil() {
+		return fmt.Errorf("cannot write a patch into %T, a non-nil pointer is required", patch)
+	}
+	ov, nv, err := diffValues(old, new)
+	if err != nil {
+		return err
+	}
+	if pv.Elem().Type() != ov.Type() {
+		return fmt.Errorf("cannot write a patch of %s values into %T", ov.Type(), patch)
+	}
+
+	d.diffStruct(ov, nv, pv.Elem(), "", map[string]Change{})
+	return nil
+}
+
+// Changes returns the changes from old to new, which must be of the same struct type, keyed by field path.
+func (d Differ) Changes(old, new any) (map[string]Change, error) {
+	ov, nv, err := diffValues(old, new)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]Change{}
+	d.diffStruct(ov, nv, reflect.New(ov.Type()).Elem(), "", changes)
+	return changes, nil
+}
+
+func diffValues(old, new any) (reflect.Value, reflect.Value, error) {
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+	if ov.Kind() == reflect.Pointer && !ov.IsNil() {
+		ov = ov.Elem()
+	}
+	if nv.Kind() == reflect.Pointer && !nv.IsNil() {
+		nv = nv.Elem()
+	}
+	if ov.Type() != nv.Type() {
+		return ov, nv, fmt.Errorf("cannot diff %T and %T values", old, new)
+	}
+	if !isMergeableStruct(ov.Type()) {
+		return ov, nv, fmt.Errorf("cannot diff %T values, a struct is required", old)
+	}
+	return ov, nv, nil
+}
+
+// diffStruct records the changes between the structs old and new, writes the changed fields into the struct patch,
+// and reports whether it found any change.
+func (d Differ) diffStruct(old, new, patch reflect.Value, path string, changes map[string]Change) bool {
+	changed := false
+	for _, f := range convertFields(old.Type(), "json", nil) {
+		if d.diffField(old.FieldByIndex(f.index), new.FieldByIndex(f.index), patch.FieldByIndex(f.index), joinPath(path, f.key), changes) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (d Differ) diffField(old, new, patch reflect.Value, path string, changes map[string]Change) bool {
+	if o, ok := old.Interface().(Optional); ok {
+		oValue, oSome := o.AnyValue()
+		nValue, nSome := new.Interface().(Optional).AnyValue()
+		elemType := o.ElemType()
+
+		if oSome && nSome && isMergeableStruct(elemType) {
+			inner := reflect.New(elemType).Elem()
+			if !d.diffStruct(valueOf(oValue, elemType), valueOf(nValue, elemType), inner, path, changes) {
+				return false
+			}
+			// inner is a T, so this cannot fail.
+			_ = patch.Addr().Interface().(OptionalPtr).SetAny(inner.Interface())
+			return true
+		}
+		if oSome == nSome && (!oSome || d.equal(valueOf(oValue, elemType), valueOf(nValue, elemType))) {
+			return false
+		}
+		if !oSome {
+			oValue = nil
+		}
+		if !nSome {
+			nValue = nil
+		}
+		changes[path] = Change{Old: oValue, New: nValue}
+		patch.Set(new)
+		return true
+	}
+
+	if isMergeableStruct(old.Type()) {
+		return d.diffStruct(old, new, patch, path, changes)
+	}
+	if d.equal(old, new) {
+		return false
+	}
+	changes[path] = Change{Old: old.Interface(), New: new.Interface()}
+	patch.Set(new)
+	return true
+}
+
+func (d Differ) equal(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Pointer {
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return d.equal(a.Elem(), b.Elem())
+	}
+
+	if m, ok := a.Type().MethodByName("Equal"); ok {
+		mt := m.Type
+		if mt.NumIn() == 2 && mt.In(1) == a.Type() && mt.NumOut() == 1 && mt.Out(0).Kind() == reflect.Bool {
+			return m.Func.Call([]reflect.Value{a, b})[0].Bool()
+		}
+	}
+	if a.Comparable() && b.Comparable() {
+		return a.Equal(b)
+	}
+	if d.Equal != nil {
+		return d.Equal(a.Interface(), b.Interface())
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,105 @@
+package opt
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diffAddress struct {
+	City Option[string] `json:"city"`
+	Zip  Option[string] `json:"zip"`
+}
+
+type diffUser struct {
+	Name      Option[string]      `json:"name"`
+	Email     Option[string]      `json:"email"`
+	Nickname  Option[string]      `json:"nickname"`
+	Age       int                 `json:"age"`
+	Tags      Option[[]string]    `json:"tags"`
+	Address   Option[diffAddress] `json:"address"`
+	Billing   diffAddress         `json:"billing"`
+	UpdatedAt Option[time.Time]   `json:"updated_at"`
+}
+
+func TestDiff(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := diffUser{
+		Name:      Some("alice"),
+		Email:     Some("alice@example.com"),
+		Nickname:  Some("al"),
+		Age:       30,
+		Tags:      Some([]string{"a"}),
+		Address:   Some(diffAddress{City: Some("Paris"), Zip: Some("75001")}),
+		UpdatedAt: Some(at),
+	}
+	new := diffUser{
+		Name:      Some("alice"),
+		Email:     Some("alice@example.org"),
+		Age:       31,
+		Tags:      Some([]string{"a", "b"}),
+		Address:   Some(diffAddress{City: Some("Lyon"), Zip: Some("75001")}),
+		Billing:   diffAddress{Zip: Some("69001")},
+		UpdatedAt: Some(at.In(time.FixedZone("CET", 3600))),
+	}
+
+	patch, err := Diff(old, new)
+	assert.NoError(t, err)
+	assert.Equal(t, diffUser{
+		Email:   Some("alice@example.org"),
+		Age:     31,
+		Tags:    Some([]string{"a", "b"}),
+		Address: Some(diffAddress{City: Some("Lyon")}),
+		Billing: diffAddress{Zip: Some("69001")},
+	}, patch)
+
+	changes, err := Changes(&old, &new)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Change{
+		"email":        {Old: "alice@example.com", New: "alice@example.org"},
+		"nickname":     {Old: "al", New: nil},
+		"age":          {Old: 30, New: 31},
+		"tags":         {Old: []string{"a"}, New: []string{"a", "b"}},
+		"address.city": {Old: "Paris", New: "Lyon"},
+		"billing.zip":  {Old: nil, New: "69001"},
+	}, changes)
+	assert.Equal(t, "al -> <nil>", changes["nickname"].String())
+
+	changes, err = Changes(old, old)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffer_Equal(t *testing.T) {
+	d := Differ{Equal: func(a, b any) bool {
+		as, bs := slices.Clone(a.([]string)), slices.Clone(b.([]string))
+		slices.Sort(as)
+		slices.Sort(bs)
+		return slices.Equal(as, bs)
+	}}
+
+	changes, err := d.Changes(
+		diffUser{Tags: Some([]string{"a", "b"}), Address: Some(diffAddress{})},
+		diffUser{Tags: Some([]string{"b", "a"})},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Change{
+		"address": {Old: diffAddress{}, New: nil},
+	}, changes)
+}
+
+func TestDiff_Errors(t *testing.T) {
+	_, err := Diff(1, 2)
+	assert.EqualError(t, err, "cannot diff int values, a struct is required")
+
+	_, err = Changes(diffUser{}, diffAddress{})
+	assert.EqualError(t, err, "cannot diff opt.diffUser and opt.diffAddress values")
+
+	err = Differ{}.Patch(diffAddress{}, diffUser{}, diffUser{})
+	assert.EqualError(t, err, "cannot write a patch into opt.diffAddress, a non-nil pointer is required")
+
+	err = Differ{}.Patch(&diffAddress{}, diffUser{}, diffUser{})
+	assert.EqualError(t, err, "cannot write a patch of opt.diffUser values into *opt.diffAddress")
+}
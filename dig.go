@@ -0,0 +1,185 @@
+package opt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrDigNotFound represents a missing map key, an out of range slice index or a nil value met while walking untyped data.
+	ErrDigNotFound = errors.New("value not found")
+	// ErrDigType represents a value of an unexpected type met while walking untyped data.
+	ErrDigType = errors.New("unexpected value type")
+	// ErrInvalidPointer represents a malformed RFC 6901 JSON Pointer.
+	ErrInvalidPointer = errors.New("invalid JSON pointer")
+)
+
+// DigError is the error returned by TryDig and TryPointer. It wraps ErrDigNotFound or ErrDigType.
+type DigError struct {
+	// Pointer is the JSON Pointer of the value where the walk failed, e.g. "/users/3" if there is no fourth user.
+	Pointer string
+	Err     error
+}
+
+func (e *DigError) Error() string {
+	return fmt.Sprintf("%q: %v", e.Pointer, e.Err)
+}
+
+func (e *DigError) Unwrap() error {
+	return e.Err
+}
+
+// Dig walks root, typically decoded from JSON into a map[string]any, along path and returns the value found as a T.
+// String path elements are map keys, and int path elements are slice or array indexes; a string holding an index can also be
+// used for slices. Any map with string keys and any slice work, not only map[string]any and []any.
+// This returns None if a value is missing along the path, or if the value found is nil or not a T.
+// Note that encoding/json decodes numbers as float64.
+func Dig[T any](root any, path ...any) Option[T] {
+	v, err := TryDig[T](root, path...)
+	if err != nil {
+		return None[T]()
+	}
+	return Some(v)
+}
+
+// TryDig does the same as Dig, and returns a *DigError telling where the walk failed instead of None.
+func TryDig[T any](root any, path ...any) (T, error) {
+	var zero T
+	v := root
+	var pointer strings.Builder
+	for _, elem := range path {
+		pointer.WriteByte('/')
+		pointer.WriteString(escapePointerToken(fmt.Sprint(elem)))
+
+		var err error
+		v, err = digStep(v, elem)
+		if err != nil {
+			return zero, &DigError{Pointer: pointer.String(), Err: err}
+		}
+	}
+
+	if v == nil {
+		return zero, &DigError{Pointer: pointer.String(), Err: ErrDigNotFound}
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, &DigError{Pointer: pointer.String(), Err: fmt.Errorf("%w: %T is not a %s", ErrDigType, v, reflect.TypeOf(&zero).Elem())}
+	}
+	return t, nil
+}
+
+// Pointer looks up the value designated by an RFC 6901 JSON Pointer, e.g. "/users/0/name", in root and returns it as a T.
+// "~1" and "~0" escape "/" and "~" in reference tokens. The pointer "" designates root itself.
+// This returns None if the pointer is malformed, or in the same cases as Dig.
+func Pointer[T any](root any, pointer string) Option[T] {
+	v, err := TryPointer[T](root, pointer)
+	if err != nil {
+		return None[T]()
+	}
+	return Some(v)
+}
+
+// TryPointer does the same as Pointer, and returns an error wrapping ErrInvalidPointer for a malformed pointer,
+// or a *DigError telling where the walk failed, instead of None.
+func TryPointer[T any](root any, pointer string) (T, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	path := make([]any, len(tokens))
+	for i, token := range tokens {
+		path[i] = token
+	}
+	return TryDig[T](root, path...)
+}
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w %q: must start with /", ErrInvalidPointer, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("%w %q: ~ must be followed by 0 or 1", ErrInvalidPointer, pointer)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// digStep returns the element key of v.
+func digStep(v any, key any) (any, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, ErrDigNotFound
+	case map[string]any:
+		k, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot index %T with %T", ErrDigType, v, key)
+		}
+		e, ok := v[k]
+		if !ok {
+			return nil, ErrDigNotFound
+		}
+		return e, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		k := reflect.ValueOf(key)
+		if !k.IsValid() || !k.Type().ConvertibleTo(rv.Type().Key()) || k.Kind() != rv.Type().Key().Kind() {
+			return nil, fmt.Errorf("%w: cannot index %T with %T", ErrDigType, v, key)
+		}
+		e := rv.MapIndex(k.Convert(rv.Type().Key()))
+		if !e.IsValid() {
+			return nil, ErrDigNotFound
+		}
+		return e.Interface(), nil
+	case reflect.Slice, reflect.Array:
+		i, ok := key.(int)
+		if key == "-" {
+			// "-" designates the element after the last one, which never exists.
+			return nil, ErrDigNotFound
+		}
+		if s, isString := key.(string); isString {
+			i, ok = parseArrayIndex(s)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot index %T with %#v", ErrDigType, v, key)
+		}
+		if i < 0 || i >= rv.Len() {
+			return nil, ErrDigNotFound
+		}
+		return rv.Index(i).Interface(), nil
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil, ErrDigNotFound
+		}
+		return digStep(rv.Elem().Interface(), key)
+	}
+	return nil, fmt.Errorf("%w: cannot index %T", ErrDigType, v)
+}
+
+// parseArrayIndex parses an RFC 6901 array index: a decimal number without leading zeros.
+func parseArrayIndex(s string) (int, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') || s[0] == '+' || s[0] == '-' {
+		return 0, false
+	}
+	i, err := strconv.Atoi(s)
+	return i, err == nil
+}
@@ -0,0 +1,96 @@
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const digDocument = `{
+	"users": [
+		{"name": "alice", "age": 30, "tags": ["a", "b"]},
+		{"name": "bob", "manager": null}
+	],
+	"a/b": {"m~n": true},
+	"": "empty"
+}`
+
+func digRoot(t *testing.T) any {
+	var root any
+	assert.NoError(t, json.Unmarshal([]byte(digDocument), &root))
+	return root
+}
+
+func TestDig(t *testing.T) {
+	root := digRoot(t)
+
+	assert.Equal(t, Some("alice"), Dig[string](root, "users", 0, "name"))
+	assert.Equal(t, Some(30.0), Dig[float64](root, "users", 0, "age"))
+	assert.Equal(t, Some("b"), Dig[string](root, "users", "0", "tags", 1))
+	assert.Equal(t, Some([]any{"a", "b"}), Dig[[]any](root, "users", 0, "tags"))
+	assert.Equal(t, Some(true), Dig[bool](root, "a/b", "m~n"))
+
+	assert.Equal(t, None[int](), Dig[int](root, "users", 0, "age"))
+	assert.Equal(t, None[string](), Dig[string](root, "users", 2, "name"))
+	assert.Equal(t, None[any](), Dig[any](root, "users", 1, "manager"))
+	assert.Equal(t, None[string](), Dig[string](root, "users", 1, "manager", "name"))
+	assert.Equal(t, None[string](), Dig[string](nil, "users"))
+
+	typed := map[string][]map[string]int{"scores": {{"math": 12}}}
+	assert.Equal(t, Some(12), Dig[int](typed, "scores", 0, "math"))
+	assert.Equal(t, Some(12), Dig[int](&typed, "scores", 0, "math"))
+}
+
+func TestTryDig(t *testing.T) {
+	root := digRoot(t)
+
+	_, err := TryDig[string](root, "users", 5, "name")
+	assert.EqualError(t, err, `"/users/5": value not found`)
+	assert.ErrorIs(t, err, ErrDigNotFound)
+
+	_, err = TryDig[string](root, "users", "first")
+	assert.EqualError(t, err, `"/users/first": unexpected value type: cannot index []interface {} with "first"`)
+	assert.ErrorIs(t, err, ErrDigType)
+
+	_, err = TryDig[string](root, "users", 0, "age")
+	assert.EqualError(t, err, `"/users/0/age": unexpected value type: float64 is not a string`)
+
+	_, err = TryDig[string](root, "users", 0, "name", "first")
+	assert.EqualError(t, err, `"/users/0/name/first": unexpected value type: cannot index string`)
+
+	_, err = TryDig[bool](root, "a/b", "x")
+	var digErr *DigError
+	assert.ErrorAs(t, err, &digErr)
+	assert.Equal(t, "/a~1b/x", digErr.Pointer)
+}
+
+func TestPointer(t *testing.T) {
+	root := digRoot(t)
+
+	assert.Equal(t, Some("bob"), Pointer[string](root, "/users/1/name"))
+	assert.Equal(t, Some(true), Pointer[bool](root, "/a~1b/m~0n"))
+	assert.Equal(t, Some("empty"), Pointer[string](root, "/"))
+	assert.True(t, Pointer[map[string]any](root, "").IsSome())
+
+	assert.Equal(t, None[string](), Pointer[string](root, "/users/01/name"))
+	assert.Equal(t, None[string](), Pointer[string](root, "/users/-/name"))
+	assert.Equal(t, None[string](), Pointer[string](root, "users"))
+}
+
+func TestTryPointer(t *testing.T) {
+	root := digRoot(t)
+
+	_, err := TryPointer[string](root, "users/0")
+	assert.EqualError(t, err, `invalid JSON pointer "users/0": must start with /`)
+	assert.ErrorIs(t, err, ErrInvalidPointer)
+
+	_, err = TryPointer[string](root, "/a~2b")
+	assert.EqualError(t, err, `invalid JSON pointer "/a~2b": ~ must be followed by 0 or 1`)
+
+	_, err = TryPointer[string](root, "/users/-")
+	assert.ErrorIs(t, err, ErrDigNotFound)
+
+	_, err = TryPointer[string](root, "/users/01")
+	assert.EqualError(t, err, `"/users/01": unexpected value type: cannot index []interface {} with "01"`)
+}
@@ -0,0 +1,112 @@
+package opt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// EnvLoader fills struct fields from environment variables.
+//
+// Fields are bound to a variable with the `env:"NAME"` tag. When the variable is set, even to an empty string,
+// its value is parsed into the field; an Option field is set to Some. When the variable is unset, the field is left untouched,
+// so an Option field of a zero value struct stays None: unset and empty variables remain distinguishable.
+//
+// Values are parsed with encoding.TextUnmarshaler when the type implements it, and with strconv otherwise.
+// time.Duration and url.URL are supported, as well as slices whose elements are separated by the `envSeparator` tag (default ",").
+//
+// Nested structs are walked recursively, and the `envPrefix:"PREFIX_"` tag prepends a prefix to the variable names of their fields.
+type EnvLoader struct {
+	// Lookup retrieves the value of a variable. Defaults to os.LookupEnv.
+	Lookup func(key string) (string, bool)
+	// Prefix is prepended to every variable name.
+	Prefix string
+}
+
+// LoadEnv fills the fields of the struct pointed by ptr from the environment variables of the process.
+// See EnvLoader for the details.
+func LoadEnv(ptr any) error {
+	return EnvLoader{}.Load(ptr)
+}
+
+// Load fills the fields of the struct pointed by ptr from environment variables.
+// This returns every parse error joined together, each one prefixed with the name of its variable.
+func (l EnvLoader) Load(ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot load environment into %T, a pointer to a struct is required", ptr)
+	}
+
+	lookup := l.Lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	var errs []error
+	loadEnvStruct(v.Elem(), l.Prefix, lookup, &errs)
+	return errors.Join(errs...)
+}
+
+func loadEnvStruct(v reflect.Value, prefix string, lookup func(string) (string, bool), errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+
+		name := f.Tag.Get("env")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			if fv.Kind() == reflect.Struct && !isLeafStruct(fv) {
+				loadEnvStruct(fv, prefix+f.Tag.Get("envPrefix"), lookup, errs)
+			}
+			continue
+		}
+		name = prefix + name
+
+		s, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		sep, ok := f.Tag.Lookup("envSeparator")
+		if !ok {
+			sep = ","
+		}
+		if err := setText(fv, s, sep); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+}
+
+// setText parses s into v, which is set to Some if it's an Option.
+func setText(v reflect.Value, s string, sep string) error {
+	if o, ok := asOptionPtr(v); ok {
+		parsed, err := parseText(s, o.ElemType(), sep)
+		if err != nil {
+			return err
+		}
+		return o.SetAny(parsed.Interface())
+	}
+
+	parsed, err := parseText(s, v.Type(), sep)
+	if err != nil {
+		return err
+	}
+	v.Set(parsed)
+	return nil
+}
+
+// isLeafStruct reports whether the struct value v must be handled as a single value rather than walked field by field.
+func isLeafStruct(v reflect.Value) bool {
+	if _, ok := asOptionPtr(v); ok {
+		return true
+	}
+	t := v.Type()
+	return t == urlType || reflect.PointerTo(t).Implements(textUnmarshalerType) || t.PkgPath() == "time"
+}
@@ -0,0 +1,128 @@
+package opt
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type envLevel int
+
+func (l *envLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return assert.AnError
+	}
+	return nil
+}
+
+type envDBConfig struct {
+	Host Option[string] `env:"HOST"`
+	Port Option[uint16] `env:"PORT"`
+}
+
+type envConfig struct {
+	Name     Option[string]        `env:"NAME"`
+	Empty    Option[string]        `env:"EMPTY"`
+	Missing  Option[string]        `env:"MISSING"`
+	Retries  Option[int]           `env:"RETRIES"`
+	Ratio    Option[float64]       `env:"RATIO"`
+	Debug    Option[bool]          `env:"DEBUG"`
+	Timeout  Option[time.Duration] `env:"TIMEOUT"`
+	Endpoint Option[url.URL]       `env:"ENDPOINT"`
+	Tags     Option[[]string]      `env:"TAGS"`
+	Ports    Option[[]int]         `env:"PORTS" envSeparator:":"`
+	Level    Option[envLevel]      `env:"LEVEL"`
+	Plain    string                `env:"PLAIN"`
+	Kept     string                `env:"KEPT"`
+	Ignored  Option[string]        `env:"-"`
+	DB       envDBConfig           `envPrefix:"DB_"`
+	Since    time.Time
+}
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestEnvLoader_Load(t *testing.T) {
+	env := map[string]string{
+		"APP_NAME":     "svc",
+		"APP_EMPTY":    "",
+		"APP_RETRIES":  "0",
+		"APP_RATIO":    "0.5",
+		"APP_DEBUG":    "true",
+		"APP_TIMEOUT":  "1m30s",
+		"APP_ENDPOINT": "https://example.com/api",
+		"APP_TAGS":     "a, b,c",
+		"APP_PORTS":    "80:443",
+		"APP_LEVEL":    "info",
+		"APP_PLAIN":    "plain",
+		"APP_IGNORED":  "x",
+		"APP_DB_HOST":  "db.local",
+	}
+
+	cfg := envConfig{Kept: "kept"}
+	err := EnvLoader{Lookup: envLookup(env), Prefix: "APP_"}.Load(&cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, envConfig{
+		Name:     Some("svc"),
+		Empty:    Some(""),
+		Missing:  None[string](),
+		Retries:  Some(0),
+		Ratio:    Some(0.5),
+		Debug:    Some(true),
+		Timeout:  Some(90 * time.Second),
+		Endpoint: Some(url.URL{Scheme: "https", Host: "example.com", Path: "/api"}),
+		Tags:     Some([]string{"a", "b", "c"}),
+		Ports:    Some([]int{80, 443}),
+		Level:    Some[envLevel](1),
+		Plain:    "plain",
+		Kept:     "kept",
+		DB: envDBConfig{
+			Host: Some("db.local"),
+			Port: None[uint16](),
+		},
+	}, cfg)
+}
+
+func TestEnvLoader_Load_Errors(t *testing.T) {
+	env := map[string]string{
+		"RETRIES":  "many",
+		"TIMEOUT":  "soon",
+		"LEVEL":    "verbose",
+		"PORTS":    "80:http",
+		"DB_PORT":  "70000",
+		"ENDPOINT": "://",
+	}
+
+	var cfg envConfig
+	err := EnvLoader{Lookup: envLookup(env)}.Load(&cfg)
+	assert.EqualError(t, err, `RETRIES: strconv.ParseInt: parsing "many": invalid syntax
+TIMEOUT: time: invalid duration "soon"
+ENDPOINT: parse "://": missing protocol scheme
+PORTS: element 1: strconv.ParseInt: parsing "http": invalid syntax
+LEVEL: assert.AnError general error for testing
+DB_PORT: strconv.ParseUint: parsing "70000": value out of range`)
+
+	err = LoadEnv(cfg)
+	assert.EqualError(t, err, "cannot load environment into opt.envConfig, a pointer to a struct is required")
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("NAME", "from-env")
+
+	var cfg envConfig
+	err := LoadEnv(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, Some("from-env"), cfg.Name)
+}
@@ -1,8 +1,10 @@
 package opt
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 )
 
 func ExampleOption_IsNone() {
@@ -266,3 +268,97 @@ func ExampleOption_OrElse() {
 	// Some[actual]
 	// Some[fallback]
 }
+
+func ExampleAnd() {
+	// WHERE active AND age >= 18
+	isAdult := func(age Option[int]) Option[bool] {
+		c := Compare(age, Some(18))
+		if c.IsNone() {
+			return None[bool]()
+		}
+		return Some(c.Unwrap() >= 0)
+	}
+
+	fmt.Println(And(Some(true), isAdult(Some(42))))
+	fmt.Println(And(Some(true), isAdult(None[int]())))
+	fmt.Println(And(Some(false), isAdult(None[int]())))
+
+	// Output:
+	// Some[true]
+	// None[]
+	// Some[false]
+}
+
+func ExampleComposePrism() {
+	type TLS struct{ Cert Option[string] }
+	type Server struct{ TLS Option[TLS] }
+	type Config struct{ Server Option[Server] }
+
+	server := OptionField(
+		func(c Config) Option[Server] { return c.Server },
+		func(c Config, v Option[Server]) Config { c.Server = v; return c },
+	)
+	tls := OptionField(
+		func(s Server) Option[TLS] { return s.TLS },
+		func(s Server, v Option[TLS]) Server { s.TLS = v; return s },
+	)
+	cert := OptionField(
+		func(t TLS) Option[string] { return t.Cert },
+		func(t TLS, v Option[string]) TLS { t.Cert = v; return t },
+	)
+	serverCert := ComposePrism(ComposePrism(server, tls), cert)
+
+	var cfg Config
+	fmt.Println(serverCert.Get(cfg))
+
+	cfg = serverCert.Set(cfg, "server.pem")
+	fmt.Println(serverCert.Get(cfg))
+
+	// Output:
+	// None[]
+	// Some[server.pem]
+}
+
+func ExampleJSONSchema() {
+	type Config struct {
+		Name    string                `json:"name"`
+		Timeout Option[time.Duration] `json:"timeout" default:"5s"`
+		Token   Option[string]        `json:"token" opt:"required"`
+	}
+
+	schema, err := JSONSchema(Config{})
+	if err != nil {
+		panic(err)
+	}
+	data, _ := json.MarshalIndent(schema, "", "  ")
+	fmt.Println(string(data))
+
+	// Output:
+	// {
+	//   "$schema": "https://json-schema.org/draft/2020-12/schema",
+	//   "properties": {
+	//     "name": {
+	//       "type": "string"
+	//     },
+	//     "timeout": {
+	//       "anyOf": [
+	//         {
+	//           "type": "integer"
+	//         },
+	//         {
+	//           "type": "null"
+	//         }
+	//       ],
+	//       "default": 5000000000
+	//     },
+	//     "token": {
+	//       "type": "string"
+	//     }
+	//   },
+	//   "required": [
+	//     "name",
+	//     "token"
+	//   ],
+	//   "type": "object"
+	// }
+}
@@ -0,0 +1,366 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldMask is a set of dotted field paths, e.g. parsed from a `?fields=name,address.city` query parameter, selecting parts of a struct.
+//
+// Path elements are the names of the `json` tags of the fields, or their field names if untagged. Untagged embedded structs are
+// flattened. A path can go through nested structs, Options and pointers holding structs, and for projections, slices and maps of them.
+// Selecting a field selects all its sub-fields.
+type FieldMask struct {
+	paths []string
+}
+
+// ParseFieldMask parses a comma-separated list of dotted field paths.
+func ParseFieldMask(s string) (FieldMask, error) {
+	if strings.TrimSpace(s) == "" {
+		return FieldMask{}, nil
+	}
+	return NewFieldMask(strings.Split(s, ",")...)
+}
+
+// NewFieldMask returns the mask selecting the given dotted field paths.
+// Paths are normalized: sorted, without duplicates, and without the paths whose parent is also selected.
+func NewFieldMask(paths ...string) (FieldMask, error) {
+	var cleaned []string
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		for _, elem := range strings.Split(p, ".") {
+			if elem == "" {
+				return FieldMask{}, fmt.Errorf("invalid field path %q", p)
+			}
+		}
+		cleaned = append(cleaned, p)
+	}
+	sort.Strings(cleaned)
+
+	var m FieldMask
+	for _, p := range cleaned {
+		if !m.Contains(p) {
+			m.paths = append(m.paths, p)
+		}
+	}
+	return m, nil
+}
+
+// Paths returns the normalized paths of the mask.
+func (m FieldMask) Paths() []string {
+	return append([]string(nil), m.paths...)
+}
+
+// IsEmpty returns true if the mask selects no field.
+func (m FieldMask) IsEmpty() bool {
+	return len(m.paths) == 0
+}
+
+// Contains reports whether the field at path is selected, either directly or through one of its parents.
+func (m FieldMask) Contains(path string) bool {
+	for _, p := range m.paths {
+		if p == path || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (m FieldMask) String() string {
+	return strings.Join(m.paths, ",")
+}
+
+// Validate checks that every path of the mask designates a field of t, which must be a struct type or a pointer to one.
+func (m FieldMask) Validate(t reflect.Type) error {
+	for _, p := range m.paths {
+		ft := t
+		for _, elem := range strings.Split(p, ".") {
+			st := maskStructType(ft)
+			if st == nil {
+				return fmt.Errorf("invalid field path %q: %s has no fields", p, ft)
+			}
+			f, ok := maskField(st, elem)
+			if !ok {
+				return fmt.Errorf("invalid field path %q: unknown field %s in %s", p, elem, st)
+			}
+			ft = f.typ
+		}
+	}
+	return nil
+}
+
+// Project blanks out the fields of the struct pointed by ptr that the mask doesn't select: Option fields are set to None
+// and other fields to their zero value. Slices and maps holding structs are projected element by element.
+func (m FieldMask) Project(ptr any) error {
+	v, err := maskTarget(m, ptr)
+	if err != nil {
+		return err
+	}
+	projectValue(v, m.tree())
+	return nil
+}
+
+// Merge copies the fields selected by the mask from src to the struct pointed by dst, which must be of the same type as src, or
+// of the type src points to. A selected field whose parent is None or nil in src is cleared in dst.
+func (m FieldMask) Merge(dst any, src any) error {
+	dv, err := maskTarget(m, dst)
+	if err != nil {
+		return err
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Kind() == reflect.Pointer && sv.Type() == reflect.TypeOf(dst) {
+		if sv.IsNil() {
+			return fmt.Errorf("cannot merge from nil %T", src)
+		}
+		sv = sv.Elem()
+	}
+	if sv.Type() != dv.Type() {
+		return fmt.Errorf("cannot merge %T into %T", src, dst)
+	}
+	if err := m.checkMergeable(dv.Type()); err != nil {
+		return err
+	}
+	return mergeMasked(dv, sv, m.tree(), "")
+}
+
+// checkMergeable checks that the paths of the mask only go through structs, Options and pointers to reach sub-fields, as
+// Merge cannot merge the sub-fields of the elements of slices, arrays and maps. This is checked before dst is changed so
+// that Merge doesn't fail halfway.
+func (m FieldMask) checkMergeable(t reflect.Type) error {
+	for _, p := range m.paths {
+		ft := t
+		elems := strings.Split(p, ".")
+		for i, elem := range elems {
+			st := ft
+			for IsOptionType(st) || st.Kind() == reflect.Pointer {
+				if IsOptionType(st) {
+					st = optionElemType(st)
+				} else {
+					st = st.Elem()
+				}
+			}
+			if !isMergeableStruct(st) {
+				return fmt.Errorf("cannot merge the sub-fields of %s, a %s", strings.Join(elems[:i], "."), ft)
+			}
+			// The mask is validated, so the field exists.
+			f, _ := maskField(st, elem)
+			ft = f.typ
+		}
+	}
+	return nil
+}
+
+// FieldMaskOf returns the mask selecting the Some Option fields of v, which must be a struct or a pointer to one.
+// Some Options, non-nil pointers and plain fields holding a struct are walked recursively, and are only selected as a whole if
+// none of their sub-fields is.
+func FieldMaskOf(v any) (FieldMask, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !isMergeableStruct(rv.Type()) {
+		return FieldMask{}, fmt.Errorf("cannot compute the field mask of %T, a struct is required", v)
+	}
+
+	var paths []string
+	collectSomePaths(rv, "", &paths)
+	return NewFieldMask(paths...)
+}
+
+// maskTree is a parsed FieldMask: each selected field maps to its selected sub-fields, or to nil if it's selected as a whole.
+type maskTree map[string]maskTree
+
+func (m FieldMask) tree() maskTree {
+	root := maskTree{}
+	for _, p := range m.paths {
+		node := root
+		elems := strings.Split(p, ".")
+		for i, elem := range elems {
+			if i == len(elems)-1 {
+				node[elem] = nil
+				break
+			}
+			child, ok := node[elem]
+			if !ok {
+				child = maskTree{}
+				node[elem] = child
+			}
+			node = child
+		}
+	}
+	return root
+}
+
+func maskTarget(m FieldMask, ptr any) (reflect.Value, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || !isMergeableStruct(v.Elem().Type()) {
+		return v, fmt.Errorf("cannot apply a field mask to %T, a pointer to a struct is required", ptr)
+	}
+	if err := m.Validate(v.Type()); err != nil {
+		return v, err
+	}
+	return v.Elem(), nil
+}
+
+// maskStructType returns the struct type found under t through Options, pointers, slices, arrays and maps, or nil.
+func maskStructType(t reflect.Type) reflect.Type {
+	for {
+		switch {
+		case IsOptionType(t):
+			t = optionElemType(t)
+		case t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map:
+			t = t.Elem()
+		case isMergeableStruct(t):
+			return t
+		default:
+			return nil
+		}
+	}
+}
+
+func maskField(t reflect.Type, name string) (convertField, bool) {
+	for _, f := range convertFields(t, "json", nil) {
+		if f.key == name {
+			return f, true
+		}
+	}
+	return convertField{}, false
+}
+
+func projectValue(v reflect.Value, tree maskTree) {
+	if o, ok := asOptionPtr(v); ok {
+		value, ok := o.AnyValue()
+		if !ok {
+			return
+		}
+		inner := valueOf(value, o.ElemType())
+		projectValue(inner, tree)
+		// inner is a T, so this cannot fail.
+		_ = o.SetAny(inner.Interface())
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			projectValue(v.Elem(), tree)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			projectValue(v.Index(i), tree)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			projectValue(value, tree)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.Struct:
+		for _, f := range convertFields(v.Type(), "json", nil) {
+			fv := v.FieldByIndex(f.index)
+			sub, ok := tree[f.key]
+			switch {
+			case !ok:
+				fv.SetZero()
+			case sub != nil:
+				projectValue(fv, sub)
+			}
+		}
+	}
+}
+
+func mergeMasked(dst, src reflect.Value, tree maskTree, path string) error {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sub := tree[name]
+		f, _ := maskField(dst.Type(), name)
+		fieldPath := joinPath(path, name)
+		df, sf := dst.FieldByIndex(f.index), src.FieldByIndex(f.index)
+		if sub == nil {
+			df.Set(sf)
+			continue
+		}
+		if err := mergeMaskedField(df, sf, sub, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeMaskedField merges the sub-fields selected by tree of the struct held by src into dst, treating a None or nil parent
+// as the zero value of the struct.
+func mergeMaskedField(dst, src reflect.Value, tree maskTree, path string) error {
+	if o, ok := asOptionPtr(dst); ok {
+		dValue, dSome := o.AnyValue()
+		sValue, sSome := src.Interface().(Optional).AnyValue()
+		if !dSome && !sSome {
+			return nil
+		}
+		inner := valueOf(dValue, o.ElemType())
+		if err := mergeMaskedField(inner, valueOf(sValue, o.ElemType()), tree, path); err != nil {
+			return err
+		}
+		// inner is a T, so this cannot fail.
+		_ = o.SetAny(inner.Interface())
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() && src.IsNil() {
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		if src.IsNil() {
+			src = reflect.New(src.Type().Elem())
+		}
+		return mergeMaskedField(dst.Elem(), src.Elem(), tree, path)
+	case reflect.Struct:
+		return mergeMasked(dst, src, tree, path)
+	default:
+		return fmt.Errorf("cannot merge the sub-fields of %s, a %s", path, dst.Type())
+	}
+}
+
+func collectSomePaths(v reflect.Value, path string, paths *[]string) {
+	for _, f := range convertFields(v.Type(), "json", nil) {
+		fv := v.FieldByIndex(f.index)
+		fieldPath := joinPath(path, f.key)
+
+		if o, ok := fv.Interface().(Optional); ok {
+			value, ok := o.AnyValue()
+			if !ok {
+				continue
+			}
+			if !collectNestedSomePaths(valueOf(value, o.ElemType()), fieldPath, paths) {
+				*paths = append(*paths, fieldPath)
+			}
+			continue
+		}
+		collectNestedSomePaths(fv, fieldPath, paths)
+	}
+}
+
+// collectNestedSomePaths collects the Some fields of the struct held by v, if any, and reports whether it found some.
+func collectNestedSomePaths(v reflect.Value, path string, paths *[]string) bool {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !isMergeableStruct(v.Type()) {
+		return false
+	}
+	before := len(*paths)
+	collectSomePaths(v, path, paths)
+	return len(*paths) > before
+}
@@ -0,0 +1,145 @@
+package opt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type maskAddress struct {
+	City Option[string] `json:"city"`
+	Zip  Option[string] `json:"zip"`
+}
+
+type maskMeta struct {
+	Version Option[int] `json:"version"`
+}
+
+type maskUser struct {
+	maskMeta
+	Name    Option[string]         `json:"name"`
+	Email   Option[string]         `json:"email"`
+	Age     int                    `json:"age"`
+	Address Option[maskAddress]    `json:"address"`
+	Home    *maskAddress           `json:"home"`
+	Others  []maskAddress          `json:"others"`
+	Labels  map[string]maskAddress `json:"labels"`
+}
+
+func TestNewFieldMask(t *testing.T) {
+	m, err := ParseFieldMask("name, address.city,address,email,name")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"address", "email", "name"}, m.Paths())
+	assert.Equal(t, "address,email,name", m.String())
+	assert.True(t, m.Contains("address.zip"))
+	assert.False(t, m.Contains("addresses"))
+	assert.False(t, m.IsEmpty())
+
+	m, err = ParseFieldMask("")
+	assert.NoError(t, err)
+	assert.True(t, m.IsEmpty())
+
+	_, err = NewFieldMask("address..city")
+	assert.EqualError(t, err, `invalid field path "address..city"`)
+}
+
+func TestFieldMask_Validate(t *testing.T) {
+	typ := reflect.TypeOf(maskUser{})
+
+	m, _ := NewFieldMask("version", "address.city", "home.zip", "others.city", "labels.zip")
+	assert.NoError(t, m.Validate(typ))
+	assert.NoError(t, m.Validate(reflect.PointerTo(typ)))
+
+	m, _ = NewFieldMask("address.country")
+	assert.EqualError(t, m.Validate(typ), `invalid field path "address.country": unknown field country in opt.maskAddress`)
+
+	m, _ = NewFieldMask("name.first")
+	assert.EqualError(t, m.Validate(typ), `invalid field path "name.first": opt.Option[string] has no fields`)
+}
+
+func TestFieldMask_Project(t *testing.T) {
+	u := maskUser{
+		maskMeta: maskMeta{Version: Some(3)},
+		Name:     Some("alice"),
+		Email:    Some("alice@example.com"),
+		Age:      30,
+		Address:  Some(maskAddress{City: Some("Paris"), Zip: Some("75001")}),
+		Home:     &maskAddress{City: Some("Lyon"), Zip: Some("69001")},
+		Others:   []maskAddress{{City: Some("Nice"), Zip: Some("06000")}},
+		Labels:   map[string]maskAddress{"work": {City: Some("Lille"), Zip: Some("59000")}},
+	}
+
+	m, _ := ParseFieldMask("name,address.city,home.zip,others.city,labels.zip")
+	assert.NoError(t, m.Project(&u))
+	assert.Equal(t, maskUser{
+		Name:    Some("alice"),
+		Address: Some(maskAddress{City: Some("Paris")}),
+		Home:    &maskAddress{Zip: Some("69001")},
+		Others:  []maskAddress{{City: Some("Nice")}},
+		Labels:  map[string]maskAddress{"work": {Zip: Some("59000")}},
+	}, u)
+
+	m, _ = ParseFieldMask("unknown")
+	assert.EqualError(t, m.Project(&u), `invalid field path "unknown": unknown field unknown in opt.maskUser`)
+	assert.EqualError(t, m.Project(u), "cannot apply a field mask to opt.maskUser, a pointer to a struct is required")
+}
+
+func TestFieldMask_Merge(t *testing.T) {
+	dst := maskUser{
+		Name:    Some("alice"),
+		Email:   Some("alice@example.com"),
+		Address: Some(maskAddress{City: Some("Paris"), Zip: Some("75001")}),
+		Home:    &maskAddress{City: Some("Lyon")},
+	}
+	src := maskUser{
+		maskMeta: maskMeta{Version: Some(4)},
+		Name:     Some("bob"),
+		Address:  Some(maskAddress{City: Some("Berlin"), Zip: Some("10115")}),
+	}
+
+	m, _ := ParseFieldMask("version,email,address.city,home.city")
+	assert.NoError(t, m.Merge(&dst, src))
+	assert.Equal(t, maskUser{
+		maskMeta: maskMeta{Version: Some(4)},
+		Name:     Some("alice"),
+		Address:  Some(maskAddress{City: Some("Berlin"), Zip: Some("75001")}),
+		Home:     &maskAddress{},
+	}, dst)
+
+	dst = maskUser{}
+	m, _ = ParseFieldMask("address.zip")
+	assert.NoError(t, m.Merge(&dst, &src))
+	assert.Equal(t, maskUser{Address: Some(maskAddress{Zip: Some("10115")})}, dst)
+
+	m, _ = ParseFieldMask("others.city")
+	assert.EqualError(t, m.Merge(&dst, src), "cannot merge the sub-fields of others, a []opt.maskAddress")
+	assert.EqualError(t, m.Merge(&dst, maskAddress{}), "cannot merge opt.maskAddress into *opt.maskUser")
+
+	// An invalid path leaves dst unchanged, even if other paths are merged before it.
+	dst = maskUser{Name: Some("alice"), Age: 30}
+	src.Labels = map[string]maskAddress{"work": {City: Some("Rome")}}
+	m, _ = ParseFieldMask("age,name,labels.city")
+	assert.EqualError(t, m.Merge(&dst, src), "cannot merge the sub-fields of labels, a map[string]opt.maskAddress")
+	assert.Equal(t, maskUser{Name: Some("alice"), Age: 30}, dst)
+}
+
+func TestFieldMaskOf(t *testing.T) {
+	m, err := FieldMaskOf(&maskUser{
+		maskMeta: maskMeta{Version: Some(1)},
+		Name:     Some("alice"),
+		Age:      30,
+		Address:  Some(maskAddress{Zip: Some("75001")}),
+		Home:     &maskAddress{},
+		Others:   []maskAddress{{City: Some("Nice")}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"address.zip", "name", "version"}, m.Paths())
+
+	m, err = FieldMaskOf(maskUser{Address: Some(maskAddress{})})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"address"}, m.Paths())
+
+	_, err = FieldMaskOf(1)
+	assert.EqualError(t, err, "cannot compute the field mask of int, a struct is required")
+}
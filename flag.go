@@ -0,0 +1,131 @@
+package opt
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// optionFlag is a flag.Value backed by an Option, which is set to Some once the flag is passed.
+type optionFlag struct {
+	o OptionalPtr
+}
+
+// Set parses the flag value into the Option. Slice elements are comma-separated.
+func (f *optionFlag) Set(s string) error {
+	v, err := parseText(s, f.o.ElemType(), ",")
+	if err != nil {
+		return err
+	}
+	return f.o.SetAny(v.Interface())
+}
+
+// String returns the value of the Option, or an empty string if it's None.
+func (f *optionFlag) String() string {
+	if f == nil || f.o == nil {
+		return ""
+	}
+	v, ok := f.o.AnyValue()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// Get returns the Option as an any value.
+// This method is required from flag.Getter interface.
+func (f *optionFlag) Get() any {
+	return reflect.ValueOf(f.o).Elem().Interface()
+}
+
+// IsBoolFlag reports whether the flag can be passed without value, which is the case for Option[bool].
+func (f *optionFlag) IsBoolFlag() bool {
+	return f.o.ElemType().Kind() == reflect.Bool
+}
+
+// FlagVar defines a flag with the given name and usage, whose value is stored in the Option pointed by o.
+// The Option is left untouched unless the flag is passed, so that a flag that is not passed is distinguishable from a flag set to the zero value.
+// If the Option is Some when the flag is defined, its value is shown as the default value in usage messages.
+// Values are parsed like LoadEnv does, and a flag of an Option[bool] can be passed without value, e.g. `-verbose`.
+func FlagVar[T any](fs *flag.FlagSet, o *Option[T], name string, usage string) {
+	fs.Var(&optionFlag{o: o}, name, usage)
+}
+
+// textFlag is a flag.Value backed by a non-Option value.
+type textFlag struct {
+	v reflect.Value
+}
+
+func (f *textFlag) Set(s string) error {
+	v, err := parseText(s, f.v.Type(), ",")
+	if err != nil {
+		return err
+	}
+	f.v.Set(v)
+	return nil
+}
+
+func (f *textFlag) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(f.v.Interface())
+}
+
+func (f *textFlag) Get() any {
+	return f.v.Interface()
+}
+
+func (f *textFlag) IsBoolFlag() bool {
+	return f.v.Kind() == reflect.Bool
+}
+
+// RegisterFlags defines a flag for every field of the struct pointed by ptr that has a `flag:"name"` tag, with the usage of its `usage:"..."` tag.
+// Option fields behave like with FlagVar, and other fields are simply set when the flag is passed.
+// Nested structs are walked recursively, and the `flagPrefix:"prefix."` tag prepends a prefix to the flag names of their fields.
+func RegisterFlags(fs *flag.FlagSet, ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot register flags of %T, a pointer to a struct is required", ptr)
+	}
+
+	var errs []error
+	registerStructFlags(fs, v.Elem(), "", &errs)
+	return errors.Join(errs...)
+}
+
+func registerStructFlags(fs *flag.FlagSet, v reflect.Value, prefix string, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+
+		name := f.Tag.Get("flag")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			if fv.Kind() == reflect.Struct && !isLeafStruct(fv) {
+				registerStructFlags(fs, fv, prefix+f.Tag.Get("flagPrefix"), errs)
+			}
+			continue
+		}
+		name = prefix + name
+
+		var value flag.Value
+		if o, ok := asOptionPtr(fv); ok {
+			value = &optionFlag{o: o}
+		} else {
+			value = &textFlag{v: fv}
+		}
+		if fs.Lookup(name) != nil {
+			*errs = append(*errs, fmt.Errorf("field %s: flag redefined: %s", f.Name, name))
+			continue
+		}
+		fs.Var(value, name, f.Tag.Get("usage"))
+	}
+}
@@ -0,0 +1,105 @@
+package opt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagVar(t *testing.T) {
+	newFlagSet := func() (*flag.FlagSet, *Option[int], *Option[bool], *Option[time.Duration]) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		retries := None[int]()
+		verbose := None[bool]()
+		timeout := Some(5 * time.Second)
+		FlagVar(fs, &retries, "retries", "number of retries")
+		FlagVar(fs, &verbose, "verbose", "verbose output")
+		FlagVar(fs, &timeout, "timeout", "timeout")
+		return fs, &retries, &verbose, &timeout
+	}
+
+	{
+		fs, retries, verbose, timeout := newFlagSet()
+		err := fs.Parse([]string{"-retries=0", "-verbose", "-timeout", "1m"})
+		assert.NoError(t, err)
+		assert.Equal(t, Some(0), *retries)
+		assert.Equal(t, Some(true), *verbose)
+		assert.Equal(t, Some(time.Minute), *timeout)
+		assert.Equal(t, Some(0), fs.Lookup("retries").Value.(flag.Getter).Get())
+	}
+
+	{
+		fs, retries, verbose, timeout := newFlagSet()
+		err := fs.Parse([]string{"-verbose=false"})
+		assert.NoError(t, err)
+		assert.Equal(t, None[int](), *retries)
+		assert.Equal(t, Some(false), *verbose)
+		assert.Equal(t, Some(5*time.Second), *timeout)
+	}
+
+	{
+		fs, _, _, _ := newFlagSet()
+		err := fs.Parse([]string{"-retries=many"})
+		assert.EqualError(t, err, `invalid value "many" for flag -retries: strconv.ParseInt: parsing "many": invalid syntax`)
+	}
+
+	{
+		fs, _, _, _ := newFlagSet()
+		var usage bytes.Buffer
+		fs.SetOutput(&usage)
+		fs.PrintDefaults()
+		assert.Equal(t, `  -retries value
+    	number of retries
+  -timeout value
+    	timeout (default 5s)
+  -verbose
+    	verbose output
+`, usage.String())
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	type DBConfig struct {
+		Host Option[string] `flag:"host" usage:"database host"`
+	}
+	type Config struct {
+		Retries Option[int]      `flag:"retries" usage:"number of retries"`
+		Tags    Option[[]string] `flag:"tags"`
+		Debug   Option[bool]     `flag:"debug"`
+		Name    string           `flag:"name"`
+		Ignored Option[int]      `flag:"-"`
+		DB      DBConfig         `flagPrefix:"db."`
+		Other   Option[int]
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg := Config{Name: "default"}
+	err := RegisterFlags(fs, &cfg)
+	assert.NoError(t, err)
+
+	err = fs.Parse([]string{"-retries", "0", "-tags=a,b", "-debug", "-db.host=localhost"})
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Retries: Some(0),
+		Tags:    Some([]string{"a", "b"}),
+		Debug:   Some(true),
+		Name:    "default",
+		DB:      DBConfig{Host: Some("localhost")},
+	}, cfg)
+	assert.Equal(t, "database host", fs.Lookup("db.host").Usage)
+	assert.Nil(t, fs.Lookup("other"))
+
+	err = fs.Parse([]string{"-name", "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", cfg.Name)
+
+	err = RegisterFlags(fs, &cfg)
+	assert.ErrorContains(t, err, "field Retries: flag redefined: retries")
+
+	err = RegisterFlags(fs, cfg)
+	assert.EqualError(t, err, "cannot register flags of opt.Config, a pointer to a struct is required")
+}
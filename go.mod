@@ -1,6 +1,6 @@
 module github.com/shimmerglass/go-optional
 
-go 1.21
+go 1.22.0
 
 require (
 	github.com/mattn/go-sqlite3 v1.14.22
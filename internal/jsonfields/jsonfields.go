@@ -0,0 +1,165 @@
+// Package jsonfields implements the rules encoding/json follows to pick the fields of the JSON encoding of a struct, for
+// any description of Go types, such as reflect or go/types, through a small adapter.
+package jsonfields
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Types adapts a description of Go types to the rules of encoding/json.
+type Types[T comparable] interface {
+	// Fields returns the fields declared by the struct type t, in order.
+	Fields(t T) []Decl[T]
+	// Pointee returns the element type of t if t is a pointer type.
+	Pointee(t T) (T, bool)
+	// IsStruct reports whether t is a struct type.
+	IsStruct(t T) bool
+	// Marshals reports whether t or *t implements json.Marshaler or encoding.TextMarshaler.
+	Marshals(t T) bool
+	// IsScalar reports whether t is a boolean, number or string type.
+	IsScalar(t T) bool
+}
+
+// Decl is a field as declared in a struct type.
+type Decl[T any] struct {
+	Name     string
+	Type     T
+	Tag      reflect.StructTag
+	Exported bool
+	Embedded bool
+}
+
+// Field is a field of the JSON encoding of a struct.
+type Field[T any] struct {
+	// Name is the name of the field in JSON.
+	Name string
+	// GoName is the name of the field in Go.
+	GoName string
+	Type   T
+	Tag    reflect.StructTag
+	// Index is the index sequence of the field, as in reflect.StructField.
+	Index  []int
+	Tagged bool
+	// ViaPointer tells whether the field is promoted through an embedded pointer, and is omitted if it's nil.
+	ViaPointer          bool
+	OmitEmpty, OmitZero bool
+	// Quoted tells whether the value is encoded in a JSON string, with the string option.
+	Quoted bool
+}
+
+// Of returns the fields of the JSON encoding of the struct type t: the fields of the embedded structs without a JSON name
+// are promoted, and of the fields with the same name, the least nested one wins, or the tagged one if there are several,
+// or none if it's still ambiguous. The fields are sorted by index sequence.
+func Of[T comparable](ts Types[T], t T) []Field[T] {
+	var all []Field[T]
+	collect(ts, t, nil, false, map[T]bool{}, &all)
+
+	byName := map[string][]Field[T]{}
+	var names []string
+	for _, f := range all {
+		if _, ok := byName[f.Name]; !ok {
+			names = append(names, f.Name)
+		}
+		byName[f.Name] = append(byName[f.Name], f)
+	}
+
+	var fields []Field[T]
+	for _, name := range names {
+		if f, ok := dominant(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].Index, fields[j].Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+func collect[T comparable](ts Types[T], t T, index []int, viaPointer bool, visiting map[T]bool, fields *[]Field[T]) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i, d := range ts.Fields(t) {
+		tag := d.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		idx := append(append([]int(nil), index...), i)
+
+		if d.Embedded {
+			ft, ptr := d.Type, false
+			if elem, ok := ts.Pointee(ft); ok {
+				ft, ptr = elem, true
+			}
+			isStruct := ts.IsStruct(ft)
+			if !d.Exported && !isStruct {
+				continue
+			}
+			if name == "" && isStruct && !ts.Marshals(ft) {
+				collect(ts, ft, idx, viaPointer || ptr, visiting, fields)
+				continue
+			}
+		} else if !d.Exported {
+			continue
+		}
+
+		f := Field[T]{Name: name, GoName: d.Name, Type: d.Type, Tag: d.Tag, Index: idx, Tagged: name != "", ViaPointer: viaPointer}
+		if f.Name == "" {
+			f.Name = d.Name
+		}
+		for _, o := range strings.Split(opts, ",") {
+			switch o {
+			case "omitempty":
+				f.OmitEmpty = true
+			case "omitzero":
+				f.OmitZero = true
+			case "string":
+				f.Quoted = isQuotable(ts, d.Type)
+			}
+		}
+		*fields = append(*fields, f)
+	}
+}
+
+// isQuotable reports whether the string option applies to t: booleans, numbers and strings, or pointers to them.
+func isQuotable[T comparable](ts Types[T], t T) bool {
+	if elem, ok := ts.Pointee(t); ok {
+		t = elem
+	}
+	return ts.IsScalar(t)
+}
+
+func dominant[T any](fields []Field[T]) (Field[T], bool) {
+	depth := len(fields[0].Index)
+	for _, f := range fields {
+		depth = min(depth, len(f.Index))
+	}
+	var shallowest, tagged []Field[T]
+	for _, f := range fields {
+		if len(f.Index) == depth {
+			shallowest = append(shallowest, f)
+			if f.Tagged {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return Field[T]{}, false
+}
@@ -0,0 +1,130 @@
+package jsonfields
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reflectTypes struct{}
+
+func (reflectTypes) Fields(t reflect.Type) []Decl[reflect.Type] {
+	fields := make([]Decl[reflect.Type], t.NumField())
+	for i := range fields {
+		f := t.Field(i)
+		fields[i] = Decl[reflect.Type]{Name: f.Name, Type: f.Type, Tag: f.Tag, Exported: f.IsExported(), Embedded: f.Anonymous}
+	}
+	return fields
+}
+
+func (reflectTypes) Pointee(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem(), true
+	}
+	return nil, false
+}
+
+func (reflectTypes) IsStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct
+}
+
+func (reflectTypes) Marshals(t reflect.Type) bool {
+	marshaler := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	return t.Implements(marshaler) || reflect.PointerTo(t).Implements(marshaler)
+}
+
+func (reflectTypes) IsScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+type Raw struct{ A int }
+
+func (Raw) MarshalJSON() ([]byte, error) { return []byte("0"), nil }
+
+type inner struct {
+	Shadowed int
+	Tie      int
+	Tagged   int `json:"Both"`
+	Deep     int
+}
+
+type other struct {
+	Tie  int
+	Both int
+}
+
+type outer struct {
+	Shadowed string
+	inner
+	*other
+	Raw
+	Skipped  int  `json:"-"`
+	Dash     int  `json:"-,"`
+	Count    *int `json:"count,string,omitempty"`
+	Names    []string
+	Zero     int `json:",omitzero,string"`
+	hidden   int
+	Embedded struct{ X int } `json:"embedded"`
+}
+
+func TestOf(t *testing.T) {
+	fields := Of[reflect.Type](reflectTypes{}, reflect.TypeOf(outer{}))
+
+	var names []string
+	byName := map[string]Field[reflect.Type]{}
+	for _, f := range fields {
+		names = append(names, f.Name)
+		byName[f.Name] = f
+	}
+	// Tie is ambiguous between inner and other, and Both of other loses to the tagged field of inner.
+	assert.Equal(t, []string{"Shadowed", "Both", "Deep", "Raw", "-", "count", "Names", "Zero", "embedded"}, names)
+
+	assert.Equal(t, reflect.TypeOf(""), byName["Shadowed"].Type)
+	assert.Equal(t, []int{1, 2}, byName["Both"].Index)
+	assert.True(t, byName["Both"].Tagged)
+	assert.Equal(t, "Tagged", byName["Both"].GoName)
+	assert.False(t, byName["Deep"].ViaPointer)
+	assert.Equal(t, "Dash", byName["-"].GoName)
+
+	count := byName["count"]
+	assert.True(t, count.Quoted)
+	assert.True(t, count.OmitEmpty)
+	assert.False(t, count.OmitZero)
+	assert.Equal(t, reflect.StructTag(`json:"count,string,omitempty"`), count.Tag)
+
+	assert.True(t, byName["Zero"].OmitZero)
+	assert.True(t, byName["Zero"].Quoted)
+	assert.False(t, byName["Names"].Quoted)
+}
+
+type promoted struct{ P int }
+
+type viaPointer struct {
+	*promoted
+}
+
+type recursive struct {
+	*recursive
+	R int
+}
+
+func TestOfViaPointer(t *testing.T) {
+	fields := Of[reflect.Type](reflectTypes{}, reflect.TypeOf(viaPointer{}))
+	if assert.Len(t, fields, 1) {
+		assert.Equal(t, "P", fields[0].Name)
+		assert.Equal(t, []int{0, 0}, fields[0].Index)
+		assert.True(t, fields[0].ViaPointer)
+	}
+
+	fields = Of[reflect.Type](reflectTypes{}, reflect.TypeOf(recursive{}))
+	if assert.Len(t, fields, 1) {
+		assert.Equal(t, []int{1}, fields[0].Index)
+		assert.False(t, fields[0].ViaPointer)
+	}
+}
@@ -0,0 +1,36 @@
+// Package opttypes recognizes the types of the opt package in go/types type information.
+package opttypes
+
+import "go/types"
+
+// PkgPath is the import path of the opt package.
+const PkgPath = "github.com/shimmerglass/go-optional"
+
+// Named returns the type arguments of t if t is an instantiation of the named generic type of the opt package, e.g. "Option".
+// The type is matched by its package path, so it is recognized whatever the name the package is imported under.
+func Named(t types.Type, name string) ([]types.Type, bool) {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil, false
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != PkgPath || obj.Name() != name {
+		return nil, false
+	}
+
+	targs := named.TypeArgs()
+	args := make([]types.Type, targs.Len())
+	for i := range args {
+		args[i] = targs.At(i)
+	}
+	return args, true
+}
+
+// OptionElem returns T if t is opt.Option[T].
+func OptionElem(t types.Type) (types.Type, bool) {
+	args, ok := Named(t, "Option")
+	if !ok || len(args) != 1 {
+		return nil, false
+	}
+	return args[0], true
+}
@@ -0,0 +1,39 @@
+package opttypes
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionElem(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "testdata/p/p.go", nil, 0)
+	assert.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("p", fset, []*ast.File{f}, nil)
+	assert.NoError(t, err)
+
+	st := pkg.Scope().Lookup("S").Type().Underlying().(*types.Struct)
+
+	elem, ok := OptionElem(st.Field(0).Type())
+	assert.True(t, ok)
+	assert.Equal(t, "string", elem.String())
+
+	_, ok = OptionElem(st.Field(1).Type())
+	assert.False(t, ok)
+
+	_, ok = OptionElem(st.Field(2).Type())
+	assert.False(t, ok)
+	args, ok := Named(st.Field(2).Type(), "JSON")
+	assert.True(t, ok)
+	assert.Equal(t, "int", args[0].String())
+
+	_, ok = OptionElem(st.Field(3).Type())
+	assert.False(t, ok)
+}
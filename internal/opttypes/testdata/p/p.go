@@ -0,0 +1,14 @@
+package p
+
+import (
+	maybe "github.com/shimmerglass/go-optional"
+)
+
+type Option[T any] struct{ v T }
+
+type S struct {
+	A maybe.Option[string]
+	B Option[string]
+	C maybe.JSON[int]
+	D string
+}
@@ -0,0 +1,354 @@
+package opt
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/shimmerglass/go-optional/internal/jsonfields"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// JSONSchema returns the JSON Schema, draft 2020-12, of the JSON encoding of the type of v, e.g. JSONSchema(Config{}).
+// Pointers are dereferenced, so that v can also be a nil pointer such as (*Config)(nil). The schema is built from the
+// rules of encoding/json and of Option's MarshalJSON and UnmarshalJSON:
+//
+//   - An Option[T] is {"anyOf": [<T schema>, {"type": "null"}]}, as None is encoded as null, and its field isn't required.
+//     With the `opt:"required"` tag that Validate checks, the field is required and is the schema of T.
+//   - The other fields are required, unless they are omitted with the omitempty or omitzero options of their `json` tag,
+//     or promoted from an embedded pointer. Pointers, slices and maps can be null.
+//   - Properties are named after their `json` tags, and the fields of embedded structs without a JSON name are promoted.
+//     The string option makes numbers and booleans strings.
+//   - The `default:"..."` tag, parsed like ApplyDefaults does, gives the default of the property.
+//   - Named struct types are defined in $defs, so that recursive types can refer to themselves, and the root type is "#".
+//     time.Time and the types implementing encoding.TextMarshaler are strings. The other types implementing json.Marshaler
+//     accept any value.
+//
+// The result can be encoded with encoding/json. Channels, functions and complex numbers cannot be encoded to JSON, and
+// every field of such a type is reported in an error, prefixed with its path.
+func JSONSchema(v any) (map[string]any, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("cannot generate the JSON Schema of a nil value")
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	g := &schemaGenerator{root: t, names: map[reflect.Type]string{}, used: map[string]bool{}, defs: map[string]any{}}
+	root := g.schema(t, t.String())
+	if len(g.errs) > 0 {
+		return nil, errors.Join(g.errs...)
+	}
+
+	s := map[string]any{"$schema": jsonSchemaDraft}
+	for k, v := range root {
+		s[k] = v
+	}
+	if len(g.defs) > 0 {
+		s["$defs"] = g.defs
+	}
+	return s, nil
+}
+
+type schemaGenerator struct {
+	root        reflect.Type
+	rootInlined bool
+	// names holds the names of the struct types in defs.
+	names map[reflect.Type]string
+	used  map[string]bool
+	defs  map[string]any
+	errs  []error
+}
+
+// schema returns the schema of t, path being where t is used, for errors.
+func (g *schemaGenerator) schema(t reflect.Type, path string) map[string]any {
+	if IsOptionType(t) {
+		return nullSchema(g.schema(optionElemType(t), path))
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == jsonNumberType:
+		return map[string]any{"type": "number"}
+	case implements(t, jsonMarshalerType):
+		return map[string]any{}
+	case implements(t, textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Pointer:
+		return nullSchema(g.schema(t.Elem(), path))
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !implements(t.Elem(), jsonMarshalerType) && !implements(t.Elem(), textMarshalerType) {
+			return nullSchema(map[string]any{"type": "string", "contentEncoding": "base64"})
+		}
+		return nullSchema(map[string]any{"type": "array", "items": g.schema(t.Elem(), path+"[]")})
+	case reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem(), path+"[]"), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Map:
+		if !isJSONMapKey(t.Key()) {
+			break
+		}
+		return nullSchema(map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem(), path+"[]")})
+	case reflect.Interface:
+		return map[string]any{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, path)
+		}
+		return g.ref(t, path)
+	}
+	g.errs = append(g.errs, fmt.Errorf("%s: %s cannot be encoded to JSON", path, t))
+	return map[string]any{}
+}
+
+// ref returns a reference to the definition of the named struct type t, adding it to the definitions if needed.
+// The root type is inlined where it's first used, i.e. at the root, and referred to as "#".
+func (g *schemaGenerator) ref(t reflect.Type, path string) map[string]any {
+	if t == g.root {
+		if !g.rootInlined {
+			g.rootInlined = true
+			return g.object(t, path)
+		}
+		return map[string]any{"$ref": "#"}
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = g.defName(t)
+		g.names[t] = name
+		g.defs[name] = g.object(t, t.String())
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// defName returns a unique name for the definition of t, made of its name with the characters that would need to be
+// escaped in a reference replaced, e.g. "Page_User" for Page[User].
+func (g *schemaGenerator) defName(t reflect.Type) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == '.' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, shortTypeName(t.Name()))
+	name = strings.Trim(name, "_")
+
+	unique := name
+	for i := 2; g.used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.used[unique] = true
+	return unique
+}
+
+// shortTypeName removes the package paths of the type arguments of a type name, e.g. "Page[User]" for
+// "Page[example.com/models.User]".
+func shortTypeName(name string) string {
+	var b strings.Builder
+	start := 0
+	for i, r := range name + "]" {
+		switch r {
+		case '[', ']', ',', ' ', '*':
+			seg := name[start:min(i, len(name))]
+			seg = seg[strings.LastIndex(seg, "/")+1:]
+			b.WriteString(seg[strings.LastIndex(seg, ".")+1:])
+			if i < len(name) {
+				b.WriteRune(r)
+			}
+			start = i + 1
+		}
+	}
+	return b.String()
+}
+
+// object returns the schema of the struct type t.
+func (g *schemaGenerator) object(t reflect.Type, path string) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for _, f := range jsonStructFields(t) {
+		fieldPath := path + "." + f.GoName
+		isOption := IsOptionType(f.Type)
+		mustBeSet := hasRequiredRule(f.Tag)
+
+		var s map[string]any
+		switch {
+		case f.Quoted:
+			s = map[string]any{"type": "string"}
+			if f.Type.Kind() == reflect.Pointer {
+				s = nullSchema(s)
+			}
+		case isOption && mustBeSet:
+			s = g.schema(optionElemType(f.Type), fieldPath)
+		default:
+			s = g.schema(f.Type, fieldPath)
+		}
+
+		if tag, ok := f.Tag.Lookup("default"); ok {
+			if def, err := schemaDefault(tag, f); err != nil {
+				g.errs = append(g.errs, fmt.Errorf("%s: %w", fieldPath, err))
+			} else {
+				s["default"] = def
+			}
+		}
+
+		properties[f.Name] = s
+		omitted := isOption || f.ViaPointer || f.OmitZero || f.OmitEmpty && isEmptyable(f.Type)
+		if mustBeSet || !omitted {
+			required = append(required, f.Name)
+		}
+	}
+
+	s := map[string]any{"type": "object"}
+	if len(properties) > 0 {
+		s["properties"] = properties
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// schemaDefault returns the JSON value of the `default` tag of f, parsed like ApplyDefaults does.
+func schemaDefault(tag string, f jsonStructField) (any, error) {
+	t := f.Type
+	if IsOptionType(t) {
+		t = optionElemType(t)
+	}
+	sep, ok := f.Tag.Lookup("defaultSeparator")
+	if !ok {
+		sep = ","
+	}
+	v, err := parseText(tag, t, sep)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if f.Quoted {
+		return string(data), nil
+	}
+	var def any
+	err = json.Unmarshal(data, &def)
+	return def, err
+}
+
+// nullSchema returns the schema of s or null. s is returned as is if it already accepts null, as the empty schema does.
+func nullSchema(s map[string]any) map[string]any {
+	if len(s) == 0 {
+		return s
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		for _, alt := range anyOf {
+			if alt, ok := alt.(map[string]any); ok && len(alt) == 1 && alt["type"] == "null" {
+				return s
+			}
+		}
+	}
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
+
+func hasRequiredRule(tag reflect.StructTag) bool {
+	for _, rule := range strings.Split(tag.Get("opt"), ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Kind() != reflect.Interface && (t.Implements(iface) || reflect.PointerTo(t).Implements(iface))
+}
+
+// isJSONMapKey reports whether encoding/json accepts t as a map key: strings, integers and encoding.TextMarshaler.
+func isJSONMapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return implements(t, textMarshalerType)
+}
+
+// isEmptyable reports whether the values of t can be empty for the omitempty option of encoding/json.
+func isEmptyable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return false
+	case reflect.Array:
+		return t.Len() == 0
+	}
+	return true
+}
+
+// jsonStructField is a field of the JSON encoding of a struct.
+type jsonStructField = jsonfields.Field[reflect.Type]
+
+// jsonStructFields returns the fields of the JSON encoding of the struct type t.
+func jsonStructFields(t reflect.Type) []jsonStructField {
+	return jsonfields.Of[reflect.Type](reflectTypes{}, t)
+}
+
+// reflectTypes adapts reflect to the rules of encoding/json.
+type reflectTypes struct{}
+
+func (reflectTypes) Fields(t reflect.Type) []jsonfields.Decl[reflect.Type] {
+	fields := make([]jsonfields.Decl[reflect.Type], t.NumField())
+	for i := range fields {
+		f := t.Field(i)
+		fields[i] = jsonfields.Decl[reflect.Type]{Name: f.Name, Type: f.Type, Tag: f.Tag, Exported: f.IsExported(), Embedded: f.Anonymous}
+	}
+	return fields
+}
+
+func (reflectTypes) Pointee(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem(), true
+	}
+	return nil, false
+}
+
+func (reflectTypes) IsStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct
+}
+
+func (reflectTypes) Marshals(t reflect.Type) bool {
+	return implements(t, jsonMarshalerType) || implements(t, textMarshalerType)
+}
+
+func (reflectTypes) IsScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaAudit struct {
+	By string    `json:"by"`
+	At time.Time `json:"at"`
+}
+
+type schemaMeta struct {
+	Source string `json:"source"`
+}
+
+type schemaPage[T any] struct {
+	Items []T            `json:"items"`
+	Next  Option[string] `json:"next"`
+	Total Option[int]    `json:"total" opt:"required"`
+}
+
+type schemaNode struct {
+	Name     string              `json:"name"`
+	Children []schemaNode        `json:"children,omitempty"`
+	Parent   Option[*schemaNode] `json:"parent,omitzero"`
+}
+
+type schemaEvent struct {
+	ID       int64                 `json:"id,string"`
+	Kind     string                `json:"kind" default:"created"`
+	At       time.Time             `json:"at"`
+	Until    Option[time.Time]     `json:"until"`
+	Timeout  Option[time.Duration] `json:"timeout" default:"5s"`
+	Tags     Option[[]string]      `json:"tags" default:"a|b" defaultSeparator:"|"`
+	Score    *float64              `json:"score,omitempty"`
+	Count    uint8                 `json:"count"`
+	Labels   map[string]string     `json:"labels"`
+	Payload  json.RawMessage       `json:"payload"`
+	Data     []byte                `json:"data"`
+	Slots    [2]Option[int]        `json:"slots"`
+	Location struct{ Lat, Lng float64 }
+	Page     schemaPage[schemaMeta] `json:"page"`
+	Tree     Option[schemaNode]     `json:"tree"`
+	Self     *schemaEvent           `json:"self,omitzero"`
+	Secret   string                 `json:"-"`
+	Extra    any                    `json:"extra"`
+	schemaMeta
+	*schemaAudit
+	internal string
+}
+
+func TestJSONSchema(t *testing.T) {
+	s, err := JSONSchema((*schemaEvent)(nil))
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"kind": {"type": "string", "default": "created"},
+			"at": {"type": "string", "format": "date-time"},
+			"until": {"anyOf": [{"type": "string", "format": "date-time"}, {"type": "null"}]},
+			"timeout": {"anyOf": [{"type": "integer"}, {"type": "null"}], "default": 5000000000},
+			"tags": {"anyOf": [{"type": "array", "items": {"type": "string"}}, {"type": "null"}], "default": ["a", "b"]},
+			"score": {"anyOf": [{"type": "number"}, {"type": "null"}]},
+			"count": {"type": "integer", "minimum": 0},
+			"labels": {"anyOf": [{"type": "object", "additionalProperties": {"type": "string"}}, {"type": "null"}]},
+			"payload": {},
+			"data": {"anyOf": [{"type": "string", "contentEncoding": "base64"}, {"type": "null"}]},
+			"slots": {"type": "array", "items": {"anyOf": [{"type": "integer"}, {"type": "null"}]}, "minItems": 2, "maxItems": 2},
+			"Location": {
+				"type": "object",
+				"properties": {"Lat": {"type": "number"}, "Lng": {"type": "number"}},
+				"required": ["Lat", "Lng"]
+			},
+			"page": {"$ref": "#/$defs/schemaPage_schemaMeta"},
+			"tree": {"anyOf": [{"$ref": "#/$defs/schemaNode"}, {"type": "null"}]},
+			"self": {"anyOf": [{"$ref": "#"}, {"type": "null"}]},
+			"extra": {},
+			"source": {"type": "string"},
+			"by": {"type": "string"}
+		},
+		"required": ["id", "kind", "at", "count", "labels", "payload", "data", "slots", "Location", "page", "extra", "source"],
+		"$defs": {
+			"schemaPage_schemaMeta": {
+				"type": "object",
+				"properties": {
+					"items": {"anyOf": [{"type": "array", "items": {"$ref": "#/$defs/schemaMeta"}}, {"type": "null"}]},
+					"next": {"anyOf": [{"type": "string"}, {"type": "null"}]},
+					"total": {"type": "integer"}
+				},
+				"required": ["items", "total"]
+			},
+			"schemaMeta": {
+				"type": "object",
+				"properties": {"source": {"type": "string"}},
+				"required": ["source"]
+			},
+			"schemaNode": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"anyOf": [{"type": "array", "items": {"$ref": "#/$defs/schemaNode"}}, {"type": "null"}]},
+					"parent": {"anyOf": [{"$ref": "#/$defs/schemaNode"}, {"type": "null"}]}
+				},
+				"required": ["name"]
+			}
+		}
+	}`, string(data))
+}
+
+func TestJSONSchema_Errors(t *testing.T) {
+	type bad struct {
+		Done   chan bool
+		Port   Option[int] `default:"http"`
+		Ratios map[float64]int
+	}
+
+	_, err := JSONSchema(bad{})
+	assert.EqualError(t, err, "opt.bad.Done: chan bool cannot be encoded to JSON\n"+
+		`opt.bad.Port: strconv.ParseInt: parsing "http": invalid syntax`+"\n"+
+		"opt.bad.Ratios: map[float64]int cannot be encoded to JSON")
+
+	_, err = JSONSchema(nil)
+	assert.EqualError(t, err, "cannot generate the JSON Schema of a nil value")
+}
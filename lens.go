@@ -0,0 +1,172 @@
+package opt
+
+import "maps"
+
+// Lens focuses on a part A of a value S that is always present, e.g. a struct field.
+// Lenses are functional: Set returns an updated copy of S.
+type Lens[S, A any] struct {
+	get func(S) A
+	set func(S, A) S
+}
+
+// NewLens returns a lens from its getter and setter. set must return an updated copy of s.
+func NewLens[S, A any](get func(s S) A, set func(s S, a A) S) Lens[S, A] {
+	return Lens[S, A]{get: get, set: set}
+}
+
+// Get returns the part of s the lens focuses on.
+func (l Lens[S, A]) Get(s S) A {
+	return l.get(s)
+}
+
+// Set returns a copy of s whose focused part is set to a.
+func (l Lens[S, A]) Set(s S, a A) S {
+	return l.set(s, a)
+}
+
+// Modify returns a copy of s whose focused part is set to f applied to its current value.
+func (l Lens[S, A]) Modify(s S, f func(A) A) S {
+	return l.set(s, f(l.get(s)))
+}
+
+// AsPrism returns the lens as a prism whose Get always returns Some, e.g. to compose it with prisms.
+func (l Lens[S, A]) AsPrism() Prism[S, A] {
+	return Prism[S, A]{
+		get: func(s S) Option[A] { return Some(l.get(s)) },
+		set: l.set,
+	}
+}
+
+// ComposeLens returns the lens focusing on the part B of the part A of S.
+func ComposeLens[S, A, B any](outer Lens[S, A], inner Lens[A, B]) Lens[S, B] {
+	return Lens[S, B]{
+		get: func(s S) B { return inner.get(outer.get(s)) },
+		set: func(s S, b B) S { return outer.set(s, inner.set(outer.get(s), b)) },
+	}
+}
+
+// NonePolicy tells what setting a value through a composed prism does when an intermediate value is missing,
+// e.g. setting the certificate of a None TLS configuration.
+type NonePolicy int
+
+const (
+	// CreateZeroOnNone replaces the missing intermediate value with its zero value, and sets the value in it. This is the default.
+	CreateZeroOnNone NonePolicy = iota
+	// SkipOnNone makes Set return the value unchanged.
+	SkipOnNone
+)
+
+// Prism focuses on a part A of a value S that may be missing, e.g. the value of an Option field, a map entry or a slice element.
+// Prisms are functional: Set returns an updated copy of S.
+type Prism[S, A any] struct {
+	get    func(S) Option[A]
+	set    func(S, A) S
+	policy NonePolicy
+}
+
+// NewPrism returns a prism from its getter and setter. set must return an updated copy of s.
+func NewPrism[S, A any](get func(s S) Option[A], set func(s S, a A) S) Prism[S, A] {
+	return Prism[S, A]{get: get, set: set}
+}
+
+// Get returns the part of s the prism focuses on, or None if it's missing.
+func (p Prism[S, A]) Get(s S) Option[A] {
+	return p.get(s)
+}
+
+// Set returns a copy of s whose focused part is set to a.
+func (p Prism[S, A]) Set(s S, a A) S {
+	return p.set(s, a)
+}
+
+// Modify returns a copy of s whose focused part is set to f applied to its current value, or s unchanged if the part is missing.
+func (p Prism[S, A]) Modify(s S, f func(A) A) S {
+	a := p.get(s)
+	if !a.isSome {
+		return s
+	}
+	return p.set(s, f(a.value))
+}
+
+// WithNonePolicy returns a copy of the prism with the given policy, which applies when it's the outer prism of a composition.
+func (p Prism[S, A]) WithNonePolicy(policy NonePolicy) Prism[S, A] {
+	p.policy = policy
+	return p
+}
+
+// ComposePrism returns the prism focusing on the part B of the part A of S.
+// When the part A is missing, Get returns None, and Set follows the NonePolicy of outer.
+func ComposePrism[S, A, B any](outer Prism[S, A], inner Prism[A, B]) Prism[S, B] {
+	return Prism[S, B]{
+		get: func(s S) Option[B] {
+			a := outer.get(s)
+			if !a.isSome {
+				return None[B]()
+			}
+			return inner.get(a.value)
+		},
+		set: func(s S, b B) S {
+			a := outer.get(s)
+			if !a.isSome && outer.policy == SkipOnNone {
+				return s
+			}
+			return outer.set(s, inner.set(a.value, b))
+		},
+		policy: inner.policy,
+	}
+}
+
+// SomePrism returns the prism focusing on the value of an Option. Set makes the Option Some.
+func SomePrism[T any]() Prism[Option[T], T] {
+	return Prism[Option[T], T]{
+		get: func(o Option[T]) Option[T] { return o },
+		set: func(_ Option[T], v T) Option[T] { return Some(v) },
+	}
+}
+
+// OptionField returns the prism focusing on the value of an Option field of S, from the field getter and setter.
+func OptionField[S, A any](get func(s S) Option[A], set func(s S, a Option[A]) S) Prism[S, A] {
+	return ComposePrism(NewLens(get, set).AsPrism(), SomePrism[A]())
+}
+
+// MapKey returns the prism focusing on the entry k of a map. Set copies the map before adding or replacing the entry.
+func MapKey[K comparable, V any](k K) Prism[map[K]V, V] {
+	return Prism[map[K]V, V]{
+		get: func(m map[K]V) Option[V] {
+			v, ok := m[k]
+			if !ok {
+				return None[V]()
+			}
+			return Some(v)
+		},
+		set: func(m map[K]V, v V) map[K]V {
+			m = maps.Clone(m)
+			if m == nil {
+				m = map[K]V{}
+			}
+			m[k] = v
+			return m
+		},
+	}
+}
+
+// SliceIndex returns the prism focusing on the element i of a slice. Set copies the slice before replacing the element,
+// and returns the slice unchanged if i is out of range, since there is no element to create.
+func SliceIndex[T any](i int) Prism[[]T, T] {
+	return Prism[[]T, T]{
+		get: func(s []T) Option[T] {
+			if i < 0 || i >= len(s) {
+				return None[T]()
+			}
+			return Some(s[i])
+		},
+		set: func(s []T, v T) []T {
+			if i < 0 || i >= len(s) {
+				return s
+			}
+			s = append([]T(nil), s...)
+			s[i] = v
+			return s
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package opt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lensTLS struct {
+	Cert Option[string]
+}
+
+type lensServer struct {
+	Port int
+	TLS  Option[lensTLS]
+}
+
+type lensConfig struct {
+	Server Option[lensServer]
+	Labels map[string]string
+	Hosts  []string
+}
+
+var (
+	lensServerPrism = OptionField(
+		func(c lensConfig) Option[lensServer] { return c.Server },
+		func(c lensConfig, v Option[lensServer]) lensConfig { c.Server = v; return c },
+	)
+	lensTLSPrism = OptionField(
+		func(s lensServer) Option[lensTLS] { return s.TLS },
+		func(s lensServer, v Option[lensTLS]) lensServer { s.TLS = v; return s },
+	)
+	lensCertPrism = OptionField(
+		func(t lensTLS) Option[string] { return t.Cert },
+		func(t lensTLS, v Option[string]) lensTLS { t.Cert = v; return t },
+	)
+	lensPortLens = NewLens(
+		func(s lensServer) int { return s.Port },
+		func(s lensServer, v int) lensServer { s.Port = v; return s },
+	)
+)
+
+func TestLens(t *testing.T) {
+	s := lensServer{Port: 80}
+	assert.Equal(t, 80, lensPortLens.Get(s))
+	assert.Equal(t, lensServer{Port: 443}, lensPortLens.Set(s, 443))
+	assert.Equal(t, lensServer{Port: 81}, lensPortLens.Modify(s, func(p int) int { return p + 1 }))
+	assert.Equal(t, lensServer{Port: 80}, s)
+
+	hosts := NewLens(
+		func(c lensConfig) []string { return c.Hosts },
+		func(c lensConfig, v []string) lensConfig { c.Hosts = v; return c },
+	)
+	first := NewLens(
+		func(h []string) string { return h[0] },
+		func(h []string, v string) []string { return append([]string{v}, h[1:]...) },
+	)
+	l := ComposeLens(hosts, first)
+	c := lensConfig{Hosts: []string{"a", "b"}}
+	assert.Equal(t, "a", l.Get(c))
+	assert.Equal(t, []string{"c", "b"}, l.Set(c, "c").Hosts)
+	assert.Equal(t, []string{"a", "b"}, c.Hosts)
+}
+
+func TestComposePrism(t *testing.T) {
+	cert := ComposePrism(ComposePrism(lensServerPrism, lensTLSPrism), lensCertPrism)
+
+	c := lensConfig{Server: Some(lensServer{Port: 443, TLS: Some(lensTLS{Cert: Some("a.pem")})})}
+	assert.Equal(t, Some("a.pem"), cert.Get(c))
+	assert.Equal(t, Some("B.PEM"), cert.Get(cert.Set(c, "B.PEM")))
+	assert.Equal(t, Some("A.PEM"), cert.Get(cert.Modify(c, func(s string) string { return "A.PEM" })))
+	assert.Equal(t, 443, cert.Set(c, "b.pem").Server.Unwrap().Port)
+	assert.Equal(t, Some("a.pem"), cert.Get(c))
+
+	assert.Equal(t, None[string](), cert.Get(lensConfig{}))
+	assert.Equal(t, lensConfig{}, cert.Modify(lensConfig{}, func(s string) string { return s }))
+	assert.Equal(t,
+		lensConfig{Server: Some(lensServer{TLS: Some(lensTLS{Cert: Some("b.pem")})})},
+		cert.Set(lensConfig{}, "b.pem"),
+	)
+
+	skip := ComposePrism(ComposePrism(lensServerPrism.WithNonePolicy(SkipOnNone), lensTLSPrism), lensCertPrism)
+	assert.Equal(t, lensConfig{}, skip.Set(lensConfig{}, "b.pem"))
+	assert.Equal(t,
+		lensConfig{Server: Some(lensServer{TLS: Some(lensTLS{Cert: Some("b.pem")})})},
+		skip.Set(lensConfig{Server: Some(lensServer{})}, "b.pem"),
+	)
+}
+
+func TestMapKey(t *testing.T) {
+	labels := ComposePrism(NewLens(
+		func(c lensConfig) map[string]string { return c.Labels },
+		func(c lensConfig, v map[string]string) lensConfig { c.Labels = v; return c },
+	).AsPrism(), MapKey[string, string]("env"))
+
+	c := lensConfig{}
+	assert.Equal(t, None[string](), labels.Get(c))
+	c = labels.Set(c, "prod")
+	assert.Equal(t, Some("prod"), labels.Get(c))
+
+	updated := labels.Set(c, "dev")
+	assert.Equal(t, map[string]string{"env": "dev"}, updated.Labels)
+	assert.Equal(t, map[string]string{"env": "prod"}, c.Labels)
+}
+
+func TestSliceIndex(t *testing.T) {
+	p := SliceIndex[int](1)
+	s := []int{1, 2}
+	assert.Equal(t, Some(2), p.Get(s))
+	assert.Equal(t, []int{1, 3}, p.Set(s, 3))
+	assert.Equal(t, []int{1, 2}, s)
+
+	assert.Equal(t, None[int](), p.Get([]int{1}))
+	assert.Equal(t, []int{1}, p.Set([]int{1}, 3))
+	assert.Equal(t, None[int](), SliceIndex[int](-1).Get(s))
+}
+
+func TestSomePrism(t *testing.T) {
+	p := SomePrism[int]()
+	assert.Equal(t, Some(1), p.Get(Some(1)))
+	assert.Equal(t, None[int](), p.Get(None[int]()))
+	assert.Equal(t, Some(2), p.Set(None[int](), 2))
+	assert.Equal(t, None[int](), p.Modify(None[int](), func(i int) int { return i + 1 }))
+}
@@ -0,0 +1,487 @@
+package opt
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MapDecoder decodes untyped data, such as a map[string]any from a dynamic source, into Go values with Option fields.
+//
+// Struct fields are matched with the map keys by the name of their tag (see TagName), or by their field name, case-insensitively.
+// Untagged embedded structs are flattened and fields tagged "-" are ignored.
+// A missing key leaves its field untouched, so an Option field of a zero value struct stays None, and a nil value gives None.
+//
+// Without WeaklyTyped, values must be of the expected kind, except that numbers are converted between types as long as they're
+// representable (e.g. a float64 holding an integral value into an int), and strings are decoded with encoding.TextUnmarshaler.
+type MapDecoder struct {
+	// TagName is the struct tag used for the key names. Defaults to "json".
+	TagName string
+	// WeaklyTyped enables weak conversions: strings are parsed into booleans, numbers and time.Duration values like LoadEnv does,
+	// numbers and booleans are formatted as strings, booleans and numbers are converted to each other (true being 1),
+	// and a single value is decoded into a slice of one element.
+	WeaklyTyped bool
+	// ErrorUnused makes Decode fail on the map keys that don't match any struct field.
+	ErrorUnused bool
+}
+
+// DecodeMap decodes input into the value pointed by output with the default MapDecoder.
+func DecodeMap(input any, output any) error {
+	return MapDecoder{}.Decode(input, output)
+}
+
+// Decode decodes input into the value pointed by output.
+// This returns every decoding error joined together, each one prefixed with the path of its value.
+func (d MapDecoder) Decode(input any, output any) error {
+	v := reflect.ValueOf(output)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("cannot decode into %T, a non-nil pointer is required", output)
+	}
+
+	var errs []error
+	d.decode(input, v.Elem(), "", &errs)
+	return errors.Join(errs...)
+}
+
+func (d MapDecoder) tagName() string {
+	if d.TagName == "" {
+		return "json"
+	}
+	return d.TagName
+}
+
+func (d MapDecoder) decode(in any, out reflect.Value, path string, errs *[]error) {
+	fail := func(err error) {
+		if path == "" {
+			*errs = append(*errs, err)
+			return
+		}
+		*errs = append(*errs, fmt.Errorf("%s: %w", path, err))
+	}
+
+	if o, ok := asOptionPtr(out); ok {
+		if in == nil {
+			o.Clear()
+			return
+		}
+		elem := reflect.New(o.ElemType()).Elem()
+		before := len(*errs)
+		d.decode(in, elem, path, errs)
+		if len(*errs) == before {
+			if err := o.SetAny(elem.Interface()); err != nil {
+				fail(err)
+			}
+		}
+		return
+	}
+
+	if in == nil {
+		out.SetZero()
+		return
+	}
+	iv := reflect.ValueOf(in)
+
+	if iv.Type().AssignableTo(out.Type()) && out.Kind() != reflect.Struct && out.Kind() != reflect.Map && out.Kind() != reflect.Slice {
+		out.Set(iv)
+		return
+	}
+
+	switch out.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(out.Type().Elem())
+		d.decode(in, elem.Elem(), path, errs)
+		out.Set(elem)
+		return
+	case reflect.Interface:
+		if iv.Type().Implements(out.Type()) {
+			out.Set(iv)
+			return
+		}
+	case reflect.Struct:
+		if !isLeafStruct(out) {
+			if iv.Kind() != reflect.Map || iv.Type().Key().Kind() != reflect.String {
+				fail(fmt.Errorf("cannot decode %T into %s", in, out.Type()))
+				return
+			}
+			d.decodeStruct(iv, out, path, errs)
+			return
+		}
+		if iv.Type().AssignableTo(out.Type()) {
+			out.Set(iv)
+			return
+		}
+	case reflect.Map:
+		if iv.Kind() != reflect.Map {
+			fail(fmt.Errorf("cannot decode %T into %s", in, out.Type()))
+			return
+		}
+		m := reflect.MakeMapWithSize(out.Type(), iv.Len())
+		iter := iv.MapRange()
+		for iter.Next() {
+			keyPath := fmt.Sprintf("%s[%v]", path, iter.Key())
+			before := len(*errs)
+			key, err := d.decodeKey(iter.Key(), out.Type().Key())
+			if err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: %w", keyPath, err))
+			}
+			value := reflect.New(out.Type().Elem()).Elem()
+			d.decode(iter.Value().Interface(), value, keyPath, errs)
+			if len(*errs) == before {
+				m.SetMapIndex(key, value)
+			}
+		}
+		out.Set(m)
+		return
+	case reflect.Slice, reflect.Array:
+		if out.Type().Elem().Kind() == reflect.Uint8 && iv.Kind() == reflect.String && out.Kind() == reflect.Slice {
+			out.SetBytes([]byte(iv.String()))
+			return
+		}
+		if iv.Kind() != reflect.Slice && iv.Kind() != reflect.Array {
+			if !d.WeaklyTyped {
+				fail(fmt.Errorf("cannot decode %T into %s", in, out.Type()))
+				return
+			}
+			iv = reflect.ValueOf([]any{in})
+		}
+		if out.Kind() == reflect.Array && iv.Len() != out.Len() {
+			fail(fmt.Errorf("cannot decode %d elements into %s", iv.Len(), out.Type()))
+			return
+		}
+		if out.Kind() == reflect.Slice {
+			out.Set(reflect.MakeSlice(out.Type(), iv.Len(), iv.Len()))
+		}
+		for i := 0; i < iv.Len(); i++ {
+			d.decode(iv.Index(i).Interface(), out.Index(i), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+		return
+	}
+
+	if err := d.decodeScalar(iv, out); err != nil {
+		fail(err)
+	}
+}
+
+// decodeKey decodes a map key, parsing string keys like LoadEnv does since object keys are always strings in most formats.
+func (d MapDecoder) decodeKey(in reflect.Value, t reflect.Type) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	if in.Kind() == reflect.String && t.Kind() != reflect.String {
+		return parseText(in.String(), t, ",")
+	}
+	var errs []error
+	d.decode(in.Interface(), key, "", &errs)
+	return key, errors.Join(errs...)
+}
+
+func (d MapDecoder) decodeStruct(in reflect.Value, out reflect.Value, path string, errs *[]error) {
+	keys := map[string]reflect.Value{}
+	iter := in.MapRange()
+	for iter.Next() {
+		keys[iter.Key().String()] = iter.Value()
+	}
+
+	used := map[string]bool{}
+	d.decodeFields(keys, used, out, path, errs)
+
+	if d.ErrorUnused {
+		var unused []string
+		for key := range keys {
+			if !used[key] {
+				unused = append(unused, key)
+			}
+		}
+		sort.Strings(unused)
+		for _, key := range unused {
+			*errs = append(*errs, fmt.Errorf("%s: unused key", joinPath(path, key)))
+		}
+	}
+}
+
+func (d MapDecoder) decodeFields(keys map[string]reflect.Value, used map[string]bool, out reflect.Value, path string, errs *[]error) {
+	t := out.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, squash, ok := mapFieldName(f, d.tagName())
+		if !ok {
+			continue
+		}
+		if squash {
+			d.decodeFields(keys, used, out.Field(i), path, errs)
+			continue
+		}
+
+		key, value, found := lookupKey(keys, name)
+		if !found {
+			continue
+		}
+		used[key] = true
+		d.decode(value.Interface(), out.Field(i), joinPath(path, name), errs)
+	}
+}
+
+// lookupKey looks up a key exactly, then case-insensitively.
+func lookupKey(keys map[string]reflect.Value, name string) (string, reflect.Value, bool) {
+	if v, ok := keys[name]; ok {
+		return name, v, true
+	}
+	for key, v := range keys {
+		if strings.EqualFold(key, name) {
+			return key, v, true
+		}
+	}
+	return "", reflect.Value{}, false
+}
+
+func (d MapDecoder) decodeScalar(in reflect.Value, out reflect.Value) error {
+	mismatch := fmt.Errorf("cannot decode %s into %s", in.Type(), out.Type())
+
+	if in.Kind() == reflect.String && out.CanAddr() {
+		if u, ok := out.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(in.String()))
+		}
+	}
+
+	switch {
+	case isNumberKind(in.Kind()) && isNumberKind(out.Kind()):
+		return convertNumber(in, out)
+	case in.Kind() == reflect.String && out.Kind() == reflect.String:
+		out.SetString(in.String())
+		return nil
+	case in.Kind() == reflect.Bool && out.Kind() == reflect.Bool:
+		out.SetBool(in.Bool())
+		return nil
+	case !d.WeaklyTyped:
+		return mismatch
+	case in.Kind() == reflect.String:
+		v, err := parseText(in.String(), out.Type(), ",")
+		if err != nil {
+			return err
+		}
+		out.Set(v)
+		return nil
+	case out.Kind() == reflect.String && (isNumberKind(in.Kind()) || in.Kind() == reflect.Bool):
+		out.SetString(fmt.Sprint(in.Interface()))
+		return nil
+	case in.Kind() == reflect.Bool && isNumberKind(out.Kind()):
+		n := 0
+		if in.Bool() {
+			n = 1
+		}
+		return convertNumber(reflect.ValueOf(n), out)
+	case isNumberKind(in.Kind()) && out.Kind() == reflect.Bool:
+		out.SetBool(!in.IsZero())
+		return nil
+	}
+	return mismatch
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return (reflect.Int <= k && k <= reflect.Uint64) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// convertNumber sets out to the number in, failing if it isn't representable by the type of out.
+func convertNumber(in reflect.Value, out reflect.Value) error {
+	var f float64
+	switch {
+	case in.CanInt():
+		f = float64(in.Int())
+	case in.CanUint():
+		f = float64(in.Uint())
+	default:
+		f = in.Float()
+	}
+
+	switch {
+	case out.CanInt():
+		if in.CanInt() {
+			if out.OverflowInt(in.Int()) {
+				return fmt.Errorf("%v overflows %s", in, out.Type())
+			}
+			out.SetInt(in.Int())
+			return nil
+		}
+		if in.CanUint() {
+			if in.Uint() > math.MaxInt64 || out.OverflowInt(int64(in.Uint())) {
+				return fmt.Errorf("%v overflows %s", in, out.Type())
+			}
+			out.SetInt(int64(in.Uint()))
+			return nil
+		}
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 || out.OverflowInt(int64(f)) {
+			return fmt.Errorf("%v is not representable by %s", in, out.Type())
+		}
+		out.SetInt(int64(f))
+	case out.CanUint():
+		if in.CanInt() {
+			if in.Int() < 0 || out.OverflowUint(uint64(in.Int())) {
+				return fmt.Errorf("%v overflows %s", in, out.Type())
+			}
+			out.SetUint(uint64(in.Int()))
+			return nil
+		}
+		if in.CanUint() {
+			if out.OverflowUint(in.Uint()) {
+				return fmt.Errorf("%v overflows %s", in, out.Type())
+			}
+			out.SetUint(in.Uint())
+			return nil
+		}
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 || out.OverflowUint(uint64(f)) {
+			return fmt.Errorf("%v is not representable by %s", in, out.Type())
+		}
+		out.SetUint(uint64(f))
+	default:
+		if out.OverflowFloat(f) {
+			return fmt.Errorf("%v overflows %s", in, out.Type())
+		}
+		out.SetFloat(f)
+	}
+	return nil
+}
+
+// MapEncoder encodes Go values with Option fields into untyped data: structs become map[string]any values, and slices []any values.
+// The keys are named after the tag of the fields (see TagName), or after their field name. Untagged embedded structs are flattened,
+// fields tagged "-" are ignored, and None fields are omitted unless NoneAsNil is set.
+type MapEncoder struct {
+	// TagName is the struct tag used for the key names. Defaults to "json".
+	TagName string
+	// NoneAsNil makes None fields encoded as nil values instead of being omitted.
+	NoneAsNil bool
+}
+
+// EncodeMap encodes the struct v, or the struct pointed by v, into a map with the default MapEncoder.
+func EncodeMap(v any) (map[string]any, error) {
+	return MapEncoder{}.Encode(v)
+}
+
+// Encode encodes the struct v, or the struct pointed by v, into a map.
+func (e MapEncoder) Encode(v any) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || isLeafStruct(addressable(rv)) {
+		return nil, fmt.Errorf("cannot encode %T into a map, a struct is required", v)
+	}
+
+	m := map[string]any{}
+	e.encodeFields(rv, m)
+	return m, nil
+}
+
+func (e MapEncoder) tagName() string {
+	if e.TagName == "" {
+		return "json"
+	}
+	return e.TagName
+}
+
+func (e MapEncoder) encode(v reflect.Value) any {
+	if o, ok := v.Interface().(Optional); ok {
+		value, ok := o.AnyValue()
+		if !ok || value == nil {
+			return nil
+		}
+		return e.encode(reflect.ValueOf(value))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return e.encode(v.Elem())
+	case reflect.Struct:
+		if isLeafStruct(addressable(v)) {
+			return v.Interface()
+		}
+		m := map[string]any{}
+		e.encodeFields(v, m)
+		return m
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = e.encode(iter.Value())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = e.encode(v.Index(i))
+		}
+		return s
+	default:
+		return v.Interface()
+	}
+}
+
+func (e MapEncoder) encodeFields(v reflect.Value, m map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, squash, ok := mapFieldName(f, e.tagName())
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if squash {
+			e.encodeFields(fv, m)
+			continue
+		}
+		if o, ok := fv.Interface().(Optional); ok && !o.IsSome() && !e.NoneAsNil {
+			continue
+		}
+		m[name] = e.encode(fv)
+	}
+}
+
+// mapFieldName returns the key name of a struct field for the given tag, whether it's an embedded struct to flatten,
+// and whether the field must be handled at all.
+func mapFieldName(f reflect.StructField, tagName string) (string, bool, bool) {
+	name, _, _ := strings.Cut(f.Tag.Get(tagName), ",")
+	if name == "-" {
+		return "", false, false
+	}
+	if name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct && !isLeafStruct(reflect.New(f.Type).Elem()) {
+		return "", true, true
+	}
+	if !f.IsExported() {
+		return "", false, false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, false, true
+}
+
+// addressable returns an addressable copy of v if it isn't addressable.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,170 @@
+package opt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapBackend struct {
+	Host Option[string] `json:"host"`
+	Port Option[uint16] `json:"port"`
+}
+
+type mapBase struct {
+	Name Option[string] `json:"name"`
+}
+
+type mapConfig struct {
+	mapBase
+	Replicas Option[int]                   `json:"replicas"`
+	Ratio    Option[float64]               `json:"ratio"`
+	Enabled  Option[bool]                  `json:"enabled"`
+	Timeout  Option[time.Duration]         `json:"timeout"`
+	Since    Option[time.Time]             `json:"since"`
+	Tags     Option[[]string]              `json:"tags"`
+	Labels   Option[map[string]string]     `json:"labels"`
+	Weights  Option[map[int]Option[int]]   `json:"weights"`
+	Backend  Option[mapBackend]            `json:"backend"`
+	Pool     []mapBackend                  `json:"pool"`
+	Extra    Option[map[string]mapBackend] `json:"extra"`
+	Plain    string
+	Ignored  Option[string] `json:"-"`
+}
+
+func TestDecodeMap(t *testing.T) {
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	input := map[string]any{
+		"name":     "svc",
+		"replicas": float64(3),
+		"ratio":    1,
+		"enabled":  nil,
+		"timeout":  int64(time.Second),
+		"since":    "2024-01-02T03:04:05Z",
+		"tags":     []any{"a", "b"},
+		"labels":   map[string]any{"env": "prod"},
+		"weights":  map[string]any{"1": 10, "2": nil},
+		"backend":  map[string]any{"host": "db.local"},
+		"pool":     []any{map[string]any{"Host": "a", "port": 80}},
+		"extra":    map[string]any{"b": map[string]any{"port": nil}},
+		"plain":    "plain",
+		"Ignored":  "x",
+	}
+
+	var cfg mapConfig
+	err := DecodeMap(input, &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, mapConfig{
+		mapBase:  mapBase{Name: Some("svc")},
+		Replicas: Some(3),
+		Ratio:    Some(1.0),
+		Enabled:  None[bool](),
+		Timeout:  Some(time.Second),
+		Since:    Some(since),
+		Tags:     Some([]string{"a", "b"}),
+		Labels:   Some(map[string]string{"env": "prod"}),
+		Weights:  Some(map[int]Option[int]{1: Some(10), 2: None[int]()}),
+		Backend:  Some(mapBackend{Host: Some("db.local")}),
+		Pool:     []mapBackend{{Host: Some("a"), Port: Some[uint16](80)}},
+		Extra:    Some(map[string]mapBackend{"b": {}}),
+		Plain:    "plain",
+	}, cfg)
+}
+
+func TestMapDecoder_Decode_Errors(t *testing.T) {
+	input := map[string]any{
+		"replicas": 1.5,
+		"enabled":  "true",
+		"tags":     "a",
+		"backend":  map[string]any{"port": -1},
+		"pool":     "x",
+		"unknown":  true,
+	}
+
+	var cfg mapConfig
+	err := MapDecoder{ErrorUnused: true}.Decode(input, &cfg)
+	assert.EqualError(t, err, `replicas: 1.5 is not representable by int
+enabled: cannot decode string into bool
+tags: cannot decode string into []string
+backend.port: -1 overflows uint16
+pool: cannot decode string into []opt.mapBackend
+unknown: unused key`)
+	assert.Equal(t, mapConfig{}, cfg)
+
+	err = DecodeMap(input, cfg)
+	assert.EqualError(t, err, "cannot decode into opt.mapConfig, a non-nil pointer is required")
+}
+
+func TestMapDecoder_Decode_WeaklyTyped(t *testing.T) {
+	input := map[string]any{
+		"name":     42,
+		"replicas": "3",
+		"ratio":    true,
+		"enabled":  "true",
+		"timeout":  "1m",
+		"tags":     "a",
+		"backend":  map[string]any{"port": "8080"},
+	}
+
+	var cfg mapConfig
+	err := MapDecoder{WeaklyTyped: true}.Decode(input, &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, mapConfig{
+		mapBase:  mapBase{Name: Some("42")},
+		Replicas: Some(3),
+		Ratio:    Some(1.0),
+		Enabled:  Some(true),
+		Timeout:  Some(time.Minute),
+		Tags:     Some([]string{"a"}),
+		Backend:  Some(mapBackend{Port: Some[uint16](8080)}),
+	}, cfg)
+}
+
+func TestMapDecoder_Decode_TagName(t *testing.T) {
+	type config struct {
+		Host Option[string] `mapstructure:"hostname"`
+	}
+
+	var cfg config
+	err := MapDecoder{TagName: "mapstructure"}.Decode(map[string]any{"hostname": "a"}, &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, Some("a"), cfg.Host)
+}
+
+func TestEncodeMap(t *testing.T) {
+	cfg := mapConfig{
+		mapBase: mapBase{Name: Some("svc")},
+		Tags:    Some([]string{"a"}),
+		Labels:  Some(map[string]string{"env": "prod"}),
+		Weights: Some(map[int]Option[int]{1: Some(10), 2: None[int]()}),
+		Backend: Some(mapBackend{Host: Some("db.local")}),
+		Pool:    []mapBackend{{Port: Some[uint16](80)}},
+		Plain:   "plain",
+		Ignored: Some("x"),
+	}
+
+	m, err := EncodeMap(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":    "svc",
+		"tags":    []any{"a"},
+		"labels":  map[string]any{"env": "prod"},
+		"weights": map[string]any{"1": 10, "2": nil},
+		"backend": map[string]any{"host": "db.local"},
+		"pool":    []any{map[string]any{"port": uint16(80)}},
+		"Plain":   "plain",
+	}, m)
+
+	var decoded mapConfig
+	assert.NoError(t, DecodeMap(m, &decoded))
+	cfg.Ignored = None[string]()
+	assert.Equal(t, cfg, decoded)
+
+	m, err = MapEncoder{NoneAsNil: true}.Encode(mapBackend{Port: Some[uint16](80)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"host": nil, "port": uint16(80)}, m)
+
+	_, err = EncodeMap(Some(1))
+	assert.EqualError(t, err, "cannot encode opt.Option[int] into a map, a struct is required")
+}
@@ -0,0 +1,216 @@
+package opt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Layer is a named configuration layer, e.g. the defaults, a configuration file, the environment or the command-line flags.
+type Layer[T any] struct {
+	Name  string
+	Value T
+}
+
+// YAMLLayer returns a layer whose value is decoded from a YAML document.
+// Option fields whose key is missing from the document stay None, and are thus left to the lower-priority layers.
+func YAMLLayer[T any](name string, data []byte) (Layer[T], error) {
+	l := Layer[T]{Name: name}
+	if err := yaml.Unmarshal(data, &l.Value); err != nil {
+		return l, fmt.Errorf("%s: %w", name, err)
+	}
+	return l, nil
+}
+
+// JSONLayer returns a layer whose value is decoded from a JSON document.
+// Option fields whose key is missing from the document, or whose value is null, stay None, and are thus left to the lower-priority layers.
+func JSONLayer[T any](name string, data []byte) (Layer[T], error) {
+	l := Layer[T]{Name: name}
+	if err := json.Unmarshal(data, &l.Value); err != nil {
+		return l, fmt.Errorf("%s: %w", name, err)
+	}
+	return l, nil
+}
+
+// Origin describes where the final value of a configuration field comes from.
+type Origin struct {
+	// Path is the dotted path of the field, made of the names of its `yaml` or `json` tags, or of its field names.
+	Path string
+	// Layer is the name of the layer that supplied the value, or "" if no layer did.
+	Layer string
+	// Value is the final value of the field. For an Option field, this is the contained value, or nil if it's None.
+	Value any
+}
+
+func (o Origin) String() string {
+	if o.Layer == "" {
+		return fmt.Sprintf("%s=%v (unset)", o.Path, o.Value)
+	}
+	return fmt.Sprintf("%s=%v (from %s)", o.Path, o.Value, o.Layer)
+}
+
+// Provenance is the list of the origins of the fields of a merged configuration, in field order.
+type Provenance []Origin
+
+// Lookup returns the origin of the field at the given path.
+func (p Provenance) Lookup(path string) (Origin, bool) {
+	for _, o := range p {
+		if o.Path == path {
+			return o, true
+		}
+	}
+	return Origin{}, false
+}
+
+// String returns one `path=value (from layer)` line per field, e.g. to implement a `--print-config` flag.
+func (p Provenance) String() string {
+	var b strings.Builder
+	for _, o := range p {
+		b.WriteString(o.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Merge overlays the layers, given in increasing priority order, into a single value of the struct type T.
+//
+// For each Option field, the value of the highest-priority layer where it is Some wins. Nested structs, including Some values of Option
+// fields holding a struct, are merged recursively. Other fields take the value of the highest-priority layer where they are not the zero value.
+//
+// This also returns the provenance of every field, telling which layer supplied its final value.
+func Merge[T any](layers ...Layer[T]) (T, Provenance, error) {
+	var merged T
+	v := reflect.ValueOf(&merged).Elem()
+	if v.Kind() != reflect.Struct {
+		return merged, nil, fmt.Errorf("cannot merge %s values, a struct type is required", v.Type())
+	}
+
+	sources := map[string]string{}
+	for _, l := range layers {
+		mergeStruct(v, reflect.ValueOf(l.Value), "", l.Name, sources)
+	}
+
+	var p Provenance
+	collectProvenance(v, "", sources, &p)
+	return merged, p, nil
+}
+
+func mergeStruct(dst, src reflect.Value, prefix, layer string, sources map[string]string) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		path := prefix + configFieldName(f)
+		dv, sv := dst.Field(i), src.Field(i)
+
+		if o, ok := asOptionPtr(dv); ok {
+			value, isSome := sv.Interface().(Optional).AnyValue()
+			if !isSome {
+				continue
+			}
+			if current, ok := o.AnyValue(); ok && isMergeableStruct(o.ElemType()) {
+				inner := reflect.New(o.ElemType()).Elem()
+				inner.Set(reflect.ValueOf(current))
+				mergeStruct(inner, reflect.ValueOf(value), path+".", layer, sources)
+				// inner and value are Ts, so this cannot fail.
+				_ = o.SetAny(inner.Interface())
+				continue
+			}
+			_ = o.SetAny(value)
+			markSources(reflect.ValueOf(value), path, layer, sources)
+			continue
+		}
+
+		if dv.Kind() == reflect.Struct && !isLeafStruct(dv) {
+			mergeStruct(dv, sv, path+".", layer, sources)
+			continue
+		}
+
+		if !sv.IsZero() {
+			dv.Set(sv)
+			sources[path] = layer
+		}
+	}
+}
+
+// markSources records the layer as the source of the field at path holding v, and of all its set sub-fields if it's a struct.
+func markSources(v reflect.Value, path, layer string, sources map[string]string) {
+	sources[path] = layer
+	// v is invalid for the nil value of an Option of an interface.
+	if !v.IsValid() || !isMergeableStruct(v.Type()) {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if o, ok := fv.Interface().(Optional); ok {
+			value, isSome := o.AnyValue()
+			if !isSome {
+				continue
+			}
+			fv = reflect.ValueOf(value)
+		} else if !isMergeableStruct(fv.Type()) && fv.IsZero() {
+			continue
+		}
+		markSources(fv, path+"."+configFieldName(f), layer, sources)
+	}
+}
+
+func collectProvenance(v reflect.Value, prefix string, sources map[string]string, p *Provenance) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		path := prefix + configFieldName(f)
+		fv := v.Field(i)
+
+		if o, ok := asOptionPtr(fv); ok {
+			value, isSome := o.AnyValue()
+			if isSome && isMergeableStruct(o.ElemType()) {
+				inner := reflect.New(o.ElemType()).Elem()
+				inner.Set(reflect.ValueOf(value))
+				collectProvenance(inner, path+".", sources, p)
+				continue
+			}
+			if !isSome {
+				value = nil
+			}
+			*p = append(*p, Origin{Path: path, Layer: sources[path], Value: value})
+			continue
+		}
+
+		if fv.Kind() == reflect.Struct && !isLeafStruct(fv) {
+			collectProvenance(fv, path+".", sources, p)
+			continue
+		}
+		*p = append(*p, Origin{Path: path, Layer: sources[path], Value: fv.Interface()})
+	}
+}
+
+// isMergeableStruct reports whether values of type t are merged field by field.
+func isMergeableStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !isLeafStruct(reflect.New(t).Elem())
+}
+
+// configFieldName returns the name of a configuration field: its `yaml` tag name, its `json` tag name or its field name.
+func configFieldName(f reflect.StructField) string {
+	for _, key := range []string{"yaml", "json"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
@@ -0,0 +1,150 @@
+package opt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mergeTLS struct {
+	Cert Option[string] `yaml:"cert" json:"cert"`
+	Key  Option[string] `yaml:"key" json:"key"`
+}
+
+type mergeServer struct {
+	Host Option[string] `yaml:"host" json:"host"`
+	Port Option[int]    `yaml:"port" json:"port"`
+}
+
+type mergeConfig struct {
+	Timeout Option[time.Duration] `yaml:"timeout" json:"timeout" env:"TIMEOUT"`
+	Retries Option[int]           `yaml:"retries" json:"retries" env:"RETRIES"`
+	Name    string                `yaml:"name" json:"name"`
+	Server  mergeServer           `yaml:"server" json:"server"`
+	TLS     Option[mergeTLS]      `yaml:"tls" json:"tls"`
+	Debug   Option[bool]          `yaml:"debug" json:"debug"`
+}
+
+func TestMerge(t *testing.T) {
+	defaults := Layer[mergeConfig]{Name: "defaults", Value: mergeConfig{
+		Timeout: Some(time.Second),
+		Retries: Some(3),
+		Name:    "app",
+		Server:  mergeServer{Host: Some("localhost"), Port: Some(8080)},
+	}}
+
+	file, err := YAMLLayer[mergeConfig]("config.yaml", []byte(`
+retries: 5
+server:
+  port: 9090
+tls:
+  cert: cert.pem
+`))
+	assert.NoError(t, err)
+
+	flags, err := JSONLayer[mergeConfig]("flags", []byte(`{"tls":{"key":"key.pem"},"debug":false}`))
+	assert.NoError(t, err)
+
+	var envCfg mergeConfig
+	err = EnvLoader{Lookup: envLookup(map[string]string{"TIMEOUT": "5s"})}.Load(&envCfg)
+	assert.NoError(t, err)
+	env := Layer[mergeConfig]{Name: "env", Value: envCfg}
+
+	merged, provenance, err := Merge(defaults, file, env, flags)
+	assert.NoError(t, err)
+	assert.Equal(t, mergeConfig{
+		Timeout: Some(5 * time.Second),
+		Retries: Some(5),
+		Name:    "app",
+		Server:  mergeServer{Host: Some("localhost"), Port: Some(9090)},
+		TLS:     Some(mergeTLS{Cert: Some("cert.pem"), Key: Some("key.pem")}),
+		Debug:   Some(false),
+	}, merged)
+
+	assert.Equal(t, `timeout=5s (from env)
+retries=5 (from config.yaml)
+name=app (from defaults)
+server.host=localhost (from defaults)
+server.port=9090 (from config.yaml)
+tls.cert=cert.pem (from config.yaml)
+tls.key=key.pem (from flags)
+debug=false (from flags)
+`, provenance.String())
+
+	origin, ok := provenance.Lookup("tls.key")
+	assert.True(t, ok)
+	assert.Equal(t, Origin{Path: "tls.key", Layer: "flags", Value: "key.pem"}, origin)
+
+	_, ok = provenance.Lookup("tls")
+	assert.False(t, ok)
+}
+
+func TestMerge_Unset(t *testing.T) {
+	merged, provenance, err := Merge(Layer[mergeConfig]{Name: "defaults"})
+	assert.NoError(t, err)
+	assert.Equal(t, mergeConfig{}, merged)
+
+	origin, ok := provenance.Lookup("tls")
+	assert.True(t, ok)
+	assert.Equal(t, "tls=<nil> (unset)", origin.String())
+
+	origin, ok = provenance.Lookup("server.port")
+	assert.True(t, ok)
+	assert.Equal(t, Origin{Path: "server.port"}, origin)
+}
+
+func TestMerge_OptionStructReplacedByLayer(t *testing.T) {
+	low := Layer[mergeConfig]{Name: "low", Value: mergeConfig{TLS: Some(mergeTLS{Cert: Some("a")})}}
+	high := Layer[mergeConfig]{Name: "high", Value: mergeConfig{TLS: Some(mergeTLS{Key: Some("b")})}}
+
+	merged, provenance, err := Merge(low, high)
+	assert.NoError(t, err)
+	assert.Equal(t, Some(mergeTLS{Cert: Some("a"), Key: Some("b")}), merged.TLS)
+
+	origin, _ := provenance.Lookup("tls.cert")
+	assert.Equal(t, "low", origin.Layer)
+	origin, _ = provenance.Lookup("tls.key")
+	assert.Equal(t, "high", origin.Layer)
+}
+
+func TestMerge_Errors(t *testing.T) {
+	_, _, err := Merge(Layer[int]{Name: "a", Value: 1})
+	assert.EqualError(t, err, "cannot merge int values, a struct type is required")
+
+	_, err = YAMLLayer[mergeConfig]("bad.yaml", []byte("retries: many"))
+	assert.ErrorContains(t, err, "bad.yaml: ")
+
+	_, err = JSONLayer[mergeConfig]("bad.json", []byte("{"))
+	assert.ErrorContains(t, err, "bad.json: ")
+}
+
+func TestMerge_PartialOptionStruct(t *testing.T) {
+	merged, provenance, err := Merge(Layer[mergeConfig]{Name: "file", Value: mergeConfig{TLS: Some(mergeTLS{Cert: Some("a")})}})
+	assert.NoError(t, err)
+	assert.Equal(t, Some(mergeTLS{Cert: Some("a")}), merged.TLS)
+
+	origin, _ := provenance.Lookup("tls.cert")
+	assert.Equal(t, "file", origin.Layer)
+	origin, _ = provenance.Lookup("tls.key")
+	assert.Equal(t, "", origin.Layer)
+}
+
+func TestMerge_SomeNilAny(t *testing.T) {
+	type inner struct {
+		Extra Option[any] `json:"extra"`
+	}
+	type config struct {
+		Extra Option[any]   `json:"extra"`
+		Inner Option[inner] `json:"inner"`
+	}
+
+	merged, provenance, err := Merge(Layer[config]{Name: "file", Value: config{Extra: Some[any](nil), Inner: Some(inner{Extra: Some[any](nil)})}})
+	assert.NoError(t, err)
+	assert.Equal(t, config{Extra: Some[any](nil), Inner: Some(inner{Extra: Some[any](nil)})}, merged)
+
+	origin, _ := provenance.Lookup("extra")
+	assert.Equal(t, "file", origin.Layer)
+	origin, _ = provenance.Lookup("inner.extra")
+	assert.Equal(t, "file", origin.Layer)
+}
@@ -0,0 +1,294 @@
+package opt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownNamedParameter represents the error that is raised when a query references a name that the argument doesn't provide.
+	ErrUnknownNamedParameter = errors.New("unknown named parameter")
+	// ErrUnusedNamedParameter represents the error that is raised when the argument provides a name that the query doesn't reference.
+	ErrUnusedNamedParameter = errors.New("unused named parameter")
+)
+
+// BindNamed rewrites a query written with `:name` or `@name` parameters into a query using the positional placeholders of the dialect,
+// and returns the arguments in the matching order.
+//
+// The argument must be a struct, a pointer to a struct or a map with string keys. Struct fields are named after their `db` tag,
+// or after their lower-cased field name when untagged; fields tagged `db:"-"` are ignored and embedded structs are flattened.
+// Argument values are passed through as-is, so an Option field binds NULL when it is None through Option.Value.
+//
+// String literals, quoted identifiers, comments, PostgreSQL dollar-quoted strings and `::` casts are left untouched, as is `@@`.
+// A PostgreSQL query cannot mix named parameters with `$n` placeholders, as their numbers would collide.
+// Every referenced name must be provided by the argument and every provided name must be referenced by the query,
+// otherwise this returns an error wrapping ErrUnknownNamedParameter and/or ErrUnusedNamedParameter.
+func BindNamed(dialect Dialect, query string, arg any) (string, []any, error) {
+	params, err := namedParams(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		b       strings.Builder
+		args    []any
+		indexes = map[string]int{}
+		unknown []string
+		// positional is the offset of the first `$n` placeholder of a PostgreSQL query, or -1.
+		positional = -1
+	)
+	b.Grow(len(query))
+
+	for i := 0; i < len(query); {
+		end, err := skipNonParam(dialect, query, i)
+		if err != nil {
+			return "", nil, err
+		}
+		if end > i {
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		if c == '$' && dialect == DialectPostgres && positional < 0 && i+1 < len(query) && '0' <= query[i+1] && query[i+1] <= '9' {
+			positional = i
+		}
+		if (c == ':' || c == '@') && i+1 < len(query) && query[i+1] == c {
+			// `::` casts and `@@` system variables
+			b.WriteString(query[i : i+2])
+			i += 2
+			continue
+		}
+		if (c != ':' && c != '@') || i+1 >= len(query) || !isNameStart(query[i+1]) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		end = i + 1
+		for end < len(query) && isNameChar(query[end]) {
+			end++
+		}
+		name := query[i+1 : end]
+		i = end
+
+		v, ok := params[name]
+		if !ok {
+			if _, seen := indexes[name]; !seen {
+				unknown = append(unknown, name)
+				indexes[name] = 0
+			}
+			continue
+		}
+		if idx, seen := indexes[name]; seen && dialect == DialectPostgres {
+			b.WriteString(dialect.placeholder(idx))
+			continue
+		}
+		args = append(args, v)
+		indexes[name] = len(args)
+		b.WriteString(dialect.placeholder(len(args)))
+	}
+
+	if positional >= 0 && len(indexes) > 0 {
+		return "", nil, fmt.Errorf("cannot mix named parameters with the $n placeholder at offset %d", positional)
+	}
+
+	var unused []string
+	for name := range params {
+		if _, ok := indexes[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+
+	var errs []error
+	if len(unknown) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownNamedParameter, strings.Join(unknown, ", ")))
+	}
+	if len(unused) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrUnusedNamedParameter, strings.Join(unused, ", ")))
+	}
+	if len(errs) > 0 {
+		return "", nil, errors.Join(errs...)
+	}
+
+	return b.String(), args, nil
+}
+
+// skipNonParam returns the end offset of the string literal, quoted identifier or comment starting at i.
+// If there is none at i, this returns i.
+func skipNonParam(dialect Dialect, query string, i int) (int, error) {
+	c := query[i]
+	next := byte(0)
+	if i+1 < len(query) {
+		next = query[i+1]
+	}
+
+	switch {
+	case c == '\'':
+		backslash := dialect == DialectMySQL ||
+			(dialect == DialectPostgres && i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i == 1 || !isNameChar(query[i-2])))
+		return skipQuoted(query, i, c, backslash)
+	case c == '"':
+		return skipQuoted(query, i, c, dialect == DialectMySQL)
+	case c == '`' && dialect == DialectMySQL:
+		return skipQuoted(query, i, c, false)
+	case c == '-' && next == '-', c == '#' && dialect == DialectMySQL:
+		end := strings.IndexByte(query[i:], '\n')
+		if end < 0 {
+			return len(query), nil
+		}
+		return i + end + 1, nil
+	case c == '/' && next == '*':
+		return skipBlockComment(query, i, dialect == DialectPostgres)
+	case c == '$' && dialect == DialectPostgres:
+		return skipDollarQuoted(query, i)
+	}
+	return i, nil
+}
+
+func skipQuoted(query string, start int, quote byte, backslash bool) (int, error) {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated %c quote at offset %d", quote, start)
+}
+
+func skipBlockComment(query string, start int, nested bool) (int, error) {
+	depth := 0
+	for i := start; i+1 < len(query); i++ {
+		switch {
+		case query[i] == '/' && query[i+1] == '*':
+			if depth == 0 || nested {
+				depth++
+			}
+			i++
+		case query[i] == '*' && query[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated comment at offset %d", start)
+}
+
+func skipDollarQuoted(query string, start int) (int, error) {
+	end := start + 1
+	if end < len(query) && isNameStart(query[end]) {
+		for end < len(query) && isNameChar(query[end]) {
+			end++
+		}
+	}
+	if end >= len(query) || query[end] != '$' {
+		// not a dollar quote, e.g. a `$1` positional parameter
+		return start, nil
+	}
+	tag := query[start : end+1]
+
+	closing := strings.Index(query[end+1:], tag)
+	if closing < 0 {
+		return 0, fmt.Errorf("unterminated %s quote at offset %d", tag, start)
+	}
+	return end + 1 + closing + len(tag), nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || ('0' <= c && c <= '9')
+}
+
+func namedParams(arg any) (map[string]any, error) {
+	params := map[string]any{}
+	if arg == nil {
+		return params, nil
+	}
+
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("nil %s named parameter argument", v.Type())
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		iter := v.MapRange()
+		for iter.Next() {
+			params[iter.Key().String()] = iter.Value().Interface()
+		}
+	case v.Kind() == reflect.Struct:
+		collectStructParams(v, params)
+	default:
+		return nil, fmt.Errorf("unsupported named parameter argument type %s", v.Type())
+	}
+	return params, nil
+}
+
+func collectStructParams(v reflect.Value, params map[string]any) {
+	var embedded []reflect.Value
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		// The exported fields of embedded structs are promoted even if their type is unexported, like encoding/json does.
+		if f.Anonymous && tag == "" {
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		params[name] = v.Field(i).Interface()
+	}
+
+	// fields of the outer struct shadow the ones of embedded structs
+	for _, ev := range embedded {
+		inner := map[string]any{}
+		collectStructParams(ev, inner)
+		for name, value := range inner {
+			if _, ok := params[name]; !ok {
+				params[name] = value
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package opt
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindNamed(t *testing.T) {
+	type Base struct {
+		ID int64 `db:"id"`
+	}
+	type Row struct {
+		Base
+		Name    Option[string] `db:"name"`
+		Age     Option[int]
+		Ignored string `db:"-"`
+		secret  string //nolint:unused
+	}
+	row := Row{Base: Base{ID: 1}, Name: Some("foo"), Age: None[int]()}
+
+	{
+		q, args, err := BindNamed(DialectSQLite, "INSERT INTO t (id, name, age) VALUES (:id, :name, @age)", row)
+		assert.NoError(t, err)
+		assert.Equal(t, "INSERT INTO t (id, name, age) VALUES (?, ?, ?)", q)
+		assert.Equal(t, []any{int64(1), Some("foo"), None[int]()}, args)
+	}
+
+	{
+		q, args, err := BindNamed(DialectPostgres, "SELECT :id::text WHERE name = :name OR alias = :name OR age = :age", &row)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT $1::text WHERE name = $2 OR alias = $2 OR age = $3", q)
+		assert.Equal(t, []any{int64(1), Some("foo"), None[int]()}, args)
+	}
+
+	{
+		q, args, err := BindNamed(DialectMySQL, "SELECT @@version, :a, :a", map[string]any{"a": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT @@version, ?, ?", q)
+		assert.Equal(t, []any{1, 1}, args)
+	}
+
+	{
+		q, args, err := BindNamed(DialectSQLite, "SELECT 1", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT 1", q)
+		assert.Empty(t, args)
+	}
+}
+
+func TestBindNamed_UnexportedEmbedded(t *testing.T) {
+	type audit struct {
+		CreatedBy Option[string] `db:"created_by"`
+		note      string         //nolint:unused
+	}
+	type timestamps struct {
+		UpdatedAt Option[int64] `db:"updated_at"`
+	}
+	type Row struct {
+		audit
+		*timestamps
+		ID int64 `db:"id"`
+	}
+	row := Row{audit: audit{CreatedBy: Some("alice")}, timestamps: &timestamps{UpdatedAt: Some(int64(2))}, ID: 1}
+
+	q, args, err := BindNamed(DialectSQLite, "UPDATE t SET created_by = :created_by, updated_at = :updated_at WHERE id = :id", row)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET created_by = ?, updated_at = ? WHERE id = ?", q)
+	assert.Equal(t, []any{Some("alice"), Some(int64(2)), int64(1)}, args)
+}
+
+func TestBindNamed_SkipsLiteralsAndComments(t *testing.T) {
+	params := map[string]any{"p": 1}
+
+	for _, tc := range []struct {
+		dialect  Dialect
+		query    string
+		expected string
+	}{
+		{DialectSQLite, `SELECT ':x', 'it''s :x', :p`, `SELECT ':x', 'it''s :x', ?`},
+		{DialectSQLite, `SELECT ":x" FROM "a "":x" WHERE a = :p`, `SELECT ":x" FROM "a "":x" WHERE a = ?`},
+		{DialectSQLite, "SELECT :p -- :x\n, 2 /* :x */", "SELECT ? -- :x\n, 2 /* :x */"},
+		{DialectMySQL, "SELECT `:x`, 'a\\':x', \":x\", :p # :x", "SELECT `:x`, 'a\\':x', \":x\", ? # :x"},
+		{DialectPostgres, `SELECT E'a\':x', :p`, `SELECT E'a\':x', $1`},
+		{DialectPostgres, `SELECT $$ :x $$, $fn$ ' :x $fn$, :p`, `SELECT $$ :x $$, $fn$ ' :x $fn$, $1`},
+		{DialectPostgres, `SELECT /* a /* :x */ :x */ :p`, `SELECT /* a /* :x */ :x */ $1`},
+		{DialectPostgres, `SELECT arr[1:2], :p::int`, `SELECT arr[1:2], $1::int`},
+	} {
+		q, _, err := BindNamed(tc.dialect, tc.query, params)
+		assert.NoError(t, err, tc.query)
+		assert.Equal(t, tc.expected, q)
+	}
+}
+
+func TestBindNamed_Errors(t *testing.T) {
+	{
+		_, _, err := BindNamed(DialectSQLite, "SELECT :a, :b, :b", map[string]any{"a": 1, "c": 2, "d": 3})
+		assert.ErrorIs(t, err, ErrUnknownNamedParameter)
+		assert.ErrorIs(t, err, ErrUnusedNamedParameter)
+		assert.EqualError(t, err, "unknown named parameter: b\nunused named parameter: c, d")
+	}
+
+	{
+		_, _, err := BindNamed(DialectSQLite, "SELECT :a", map[string]any{"a": 1, "b": 2})
+		assert.ErrorIs(t, err, ErrUnusedNamedParameter)
+		assert.NotErrorIs(t, err, ErrUnknownNamedParameter)
+	}
+
+	{
+		_, _, err := BindNamed(DialectSQLite, "SELECT ':a", map[string]any{"a": 1})
+		assert.EqualError(t, err, "unterminated ' quote at offset 7")
+	}
+
+	{
+		_, _, err := BindNamed(DialectSQLite, "SELECT :a /* x", map[string]any{"a": 1})
+		assert.EqualError(t, err, "unterminated comment at offset 10")
+	}
+
+	{
+		_, _, err := BindNamed(DialectPostgres, "SELECT $1, :a", map[string]any{"a": 1})
+		assert.EqualError(t, err, "cannot mix named parameters with the $n placeholder at offset 7")
+
+		q, _, err := BindNamed(DialectPostgres, "SELECT $1", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT $1", q)
+	}
+
+	{
+		_, _, err := BindNamed(DialectSQLite, "SELECT :a", 42)
+		assert.EqualError(t, err, "unsupported named parameter argument type int")
+	}
+
+	{
+		var m *map[string]any
+		_, _, err := BindNamed(DialectSQLite, "SELECT :a", m)
+		assert.EqualError(t, err, "nil *map[string]interface {} named parameter argument")
+	}
+}
+
+func TestBindNamed_SQL(t *testing.T) {
+	tmpfile, err := os.CreateTemp(os.TempDir(), "testdb")
+	assert.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", tmpfile.Name())
+	assert.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	_, err = db.Exec("CREATE TABLE test_table (id INTEGER NOT NULL PRIMARY KEY, name VARCHAR(32));")
+	assert.NoError(t, err)
+
+	type Row struct {
+		ID   int64          `db:"id"`
+		Name Option[string] `db:"name"`
+	}
+	for _, row := range []Row{{ID: 1, Name: Some("foo")}, {ID: 2, Name: None[string]()}} {
+		q, args, err := BindNamed(DialectSQLite, "INSERT INTO test_table(id, name) VALUES (:id, :name)", row)
+		assert.NoError(t, err)
+		_, err = db.Exec(q, args...)
+		assert.NoError(t, err)
+	}
+
+	var maybeName Option[string]
+
+	err = db.QueryRow("SELECT name FROM test_table WHERE id = 1").Scan(&maybeName)
+	assert.NoError(t, err)
+	assert.Equal(t, Some("foo"), maybeName)
+
+	err = db.QueryRow("SELECT name FROM test_table WHERE id = 2").Scan(&maybeName)
+	assert.NoError(t, err)
+	assert.True(t, maybeName.IsNone())
+}
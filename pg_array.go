@@ -0,0 +1,342 @@
+package opt
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PGArray is a PostgreSQL array whose elements may be NULL. It is encoded to and decoded from the array text format, e.g. `{1,NULL,3}`.
+// A multi-dimensional array is a PGArray of PGArray values, e.g. PGArray[PGArray[int]] for `{{1,2},{3,NULL}}`.
+// This type implements database/sql/driver.Valuer and database/sql.Scanner; use Option[PGArray[T]] for a nullable array column.
+type PGArray[T any] []Option[T]
+
+// pgArrayAppender is implemented by PGArray so that nested arrays are written unquoted into their parent literal.
+type pgArrayAppender interface {
+	appendPGArray(b *strings.Builder) error
+}
+
+// Value returns the array text format representation of the array.
+// This method is required from database/sql/driver.Valuer interface.
+func (a PGArray[T]) Value() (driver.Value, error) {
+	var b strings.Builder
+	if err := a.appendPGArray(&b); err != nil {
+		return nil, err
+	}
+	return b.String(), nil
+}
+
+func (a PGArray[T]) appendPGArray(b *strings.Builder) error {
+	b.WriteByte('{')
+	for i, elem := range a {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if elem.IsNone() {
+			b.WriteString("NULL")
+			continue
+		}
+
+		if sub, ok := any(elem.Unwrap()).(pgArrayAppender); ok {
+			if err := sub.appendPGArray(b); err != nil {
+				return err
+			}
+			continue
+		}
+
+		v, err := driver.DefaultParameterConverter.ConvertValue(elem.Unwrap())
+		if err != nil {
+			return fmt.Errorf("array element %d: %w", i, err)
+		}
+		if v == nil {
+			b.WriteString("NULL")
+			continue
+		}
+		appendPGArrayElem(b, formatPGArrayElem(v))
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+func formatPGArrayElem(v driver.Value) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		if v {
+			return "t"
+		}
+		return "f"
+	case []byte:
+		return `\x` + hex.EncodeToString(v)
+	case time.Time:
+		return v.Format(pgTimestampLayouts[0])
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// pgTimestampLayouts are the layouts of the timestamps in arrays: the one Value writes, the ones PostgreSQL writes for
+// timestamptz, with an hour offset such as "+02", and timestamp values, and dates.
+var pgTimestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parsePGTimestamp(s string) (time.Time, error) {
+	for _, layout := range pgTimestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a timestamp", s)
+}
+
+func appendPGArrayElem(b *strings.Builder, s string) {
+	if s != "" && !strings.EqualFold(s, "NULL") && !strings.ContainsAny(s, "{}\",\\ \t\n\r\v\f") {
+		b.WriteString(s)
+		return
+	}
+
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+}
+
+// Scan assigns a value in the array text format from a database driver.
+// Each non-NULL element is converted into T like Option.Scan does with a string source, except time.Time elements,
+// which are parsed from the timestamp format Value writes or the ones PostgreSQL writes.
+// This method is required from database/sql.Scanner interface.
+func (a *PGArray[T]) Scan(src any) error {
+	var s string
+	switch src := src.(type) {
+	case string:
+		s = src
+	case []byte:
+		s = string(src)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into %T, use an Option of it instead", a)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, a)
+	}
+
+	elems, err := parsePGArray(s)
+	if err != nil {
+		return err
+	}
+
+	arr := make(PGArray[T], len(elems))
+	for i, elem := range elems {
+		if elem == nil {
+			arr[i] = None[T]()
+			continue
+		}
+
+		var v T
+		if bs, ok := any(&v).(*[]byte); ok && strings.HasPrefix(*elem, `\x`) {
+			*bs, err = hex.DecodeString((*elem)[2:])
+		} else if tp, ok := any(&v).(*time.Time); ok {
+			*tp, err = parsePGTimestamp(*elem)
+		} else {
+			err = sqlConvertAssign(&v, *elem)
+		}
+		if err != nil {
+			return fmt.Errorf("array element %d: %w", i, err)
+		}
+		arr[i] = Some(v)
+	}
+
+	*a = arr
+	return nil
+}
+
+// parsePGArray splits the top level of an array literal into its elements, with nil standing for NULL.
+// Nested arrays are returned as their raw literal.
+func parsePGArray(s string) ([]*string, error) {
+	p := pgArrayParser{s: s}
+
+	// optional dimension decoration, e.g. `[0:2]={1,2,3}`
+	if strings.HasPrefix(s, "[") {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return nil, p.errorf("missing \"=\" after array dimensions")
+		}
+		p.pos = eq + 1
+	}
+
+	p.skipSpaces()
+	if !p.consume('{') {
+		return nil, p.errorf("expected \"{\"")
+	}
+
+	elems := []*string{}
+	p.skipSpaces()
+	if p.consume('}') {
+		return elems, p.end()
+	}
+
+	for {
+		p.skipSpaces()
+		elem, err := p.elem()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+
+		p.skipSpaces()
+		if p.consume(',') {
+			continue
+		}
+		if p.consume('}') {
+			return elems, p.end()
+		}
+		return nil, p.errorf("expected \",\" or \"}\"")
+	}
+}
+
+type pgArrayParser struct {
+	s   string
+	pos int
+}
+
+func (p *pgArrayParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("malformed array literal %q at offset %d: %s", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pgArrayParser) skipSpaces() {
+	for p.pos < len(p.s) && isPGArraySpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *pgArrayParser) consume(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *pgArrayParser) end() error {
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return p.errorf("unexpected trailing characters")
+	}
+	return nil
+}
+
+func (p *pgArrayParser) elem() (*string, error) {
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch p.s[p.pos] {
+	case '{':
+		return p.subArray()
+	case '"':
+		return p.quoted()
+	case ',', '}':
+		return nil, p.errorf("empty element")
+	default:
+		return p.unquoted()
+	}
+}
+
+func (p *pgArrayParser) subArray() (*string, error) {
+	start := p.pos
+	depth := 0
+	for ; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				sub := p.s[start:p.pos]
+				return &sub, nil
+			}
+		case '"':
+			if _, err := p.quoted(); err != nil {
+				return nil, err
+			}
+			p.pos--
+		case '\\':
+			p.pos++
+		}
+	}
+	return nil, p.errorf("unterminated sub-array")
+}
+
+func (p *pgArrayParser) quoted() (*string, error) {
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		switch c := p.s[p.pos]; c {
+		case '\\':
+			p.pos++
+			if p.pos >= len(p.s) {
+				return nil, p.errorf("unterminated quoted element")
+			}
+			b.WriteByte(p.s[p.pos])
+		case '"':
+			p.pos++
+			v := b.String()
+			return &v, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return nil, p.errorf("unterminated quoted element")
+}
+
+func (p *pgArrayParser) unquoted() (*string, error) {
+	var b strings.Builder
+	escaped := false
+	trailingSpaces := 0
+	for ; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		switch {
+		case c == ',' || c == '}':
+			v := b.String()
+			v = v[:len(v)-trailingSpaces]
+			if !escaped && strings.EqualFold(v, "NULL") {
+				return nil, nil
+			}
+			return &v, nil
+		case c == '{' || c == '"':
+			return nil, p.errorf("unexpected %q in unquoted element", c)
+		case c == '\\':
+			p.pos++
+			if p.pos >= len(p.s) {
+				return nil, p.errorf("unexpected end of input")
+			}
+			b.WriteByte(p.s[p.pos])
+			escaped = true
+			trailingSpaces = 0
+		default:
+			b.WriteByte(c)
+			if isPGArraySpace(c) {
+				trailingSpaces++
+			} else {
+				trailingSpaces = 0
+			}
+		}
+	}
+	return nil, p.errorf("unexpected end of input")
+}
+
+func isPGArraySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
@@ -0,0 +1,182 @@
+package opt
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPGArray_Value(t *testing.T) {
+	for _, tc := range []struct {
+		arr      driver.Valuer
+		expected string
+	}{
+		{PGArray[int]{Some(1), None[int](), Some(3)}, `{1,NULL,3}`},
+		{PGArray[int]{}, `{}`},
+		{PGArray[int](nil), `{}`},
+		{PGArray[float64]{Some(1.5), Some(-2.0)}, `{1.5,-2}`},
+		{PGArray[bool]{Some(true), Some(false), None[bool]()}, `{t,f,NULL}`},
+		{PGArray[string]{Some("foo"), Some(""), Some("NULL"), Some("null"), None[string]()}, `{foo,"","NULL","null",NULL}`},
+		{PGArray[string]{Some(`a "b"`), Some(`c\d`), Some("e,f"), Some("{g}"), Some(" h ")}, `{"a \"b\"","c\\d","e,f","{g}"," h "}`},
+		{PGArray[[]byte]{Some([]byte("hi"))}, `{"\\x6869"}`},
+		{PGArray[PGArray[int]]{Some(PGArray[int]{Some(1), Some(2)}), Some(PGArray[int]{Some(3), None[int]()})}, `{{1,2},{3,NULL}}`},
+		{PGArray[PGArray[string]]{Some(PGArray[string]{Some("a b"), Some("}")})}, `{{"a b","}"}}`},
+	} {
+		v, err := tc.arr.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, v)
+	}
+}
+
+func TestPGArray_Value_UnsupportedTypes(t *testing.T) {
+	type ustruct struct {
+		A int
+	}
+
+	_, err := PGArray[ustruct]{Some(ustruct{})}.Value()
+	assert.Error(t, err)
+}
+
+func TestPGArray_Scan(t *testing.T) {
+	{
+		var arr PGArray[int]
+		err := arr.Scan(`{1,NULL,3}`)
+		assert.NoError(t, err)
+		assert.Equal(t, PGArray[int]{Some(1), None[int](), Some(3)}, arr)
+	}
+
+	{
+		var arr PGArray[int]
+		err := arr.Scan([]byte(`[0:2]={ 1 , null ,3 }`))
+		assert.NoError(t, err)
+		assert.Equal(t, PGArray[int]{Some(1), None[int](), Some(3)}, arr)
+	}
+
+	{
+		arr := PGArray[int]{Some(1)}
+		err := arr.Scan(`{}`)
+		assert.NoError(t, err)
+		assert.Equal(t, PGArray[int]{}, arr)
+	}
+
+	{
+		var arr PGArray[string]
+		err := arr.Scan(`{foo,"","NULL",NULL,"a \"b\"","c\\d",e\,f, g h ,N\ULL}`)
+		assert.NoError(t, err)
+		assert.Equal(t, PGArray[string]{
+			Some("foo"), Some(""), Some("NULL"), None[string](), Some(`a "b"`), Some(`c\d`), Some("e,f"), Some("g h"), Some("NULL"),
+		}, arr)
+	}
+
+	{
+		var arr PGArray[bool]
+		err := arr.Scan(`{t,f,NULL}`)
+		assert.NoError(t, err)
+		assert.Equal(t, PGArray[bool]{Some(true), Some(false), None[bool]()}, arr)
+	}
+
+	{
+		var arr PGArray[[]byte]
+		err := arr.Scan(`{"\\x6869",plain}`)
+		assert.NoError(t, err)
+		assert.Equal(t, PGArray[[]byte]{Some([]byte("hi")), Some([]byte("plain"))}, arr)
+	}
+
+	{
+		var arr PGArray[PGArray[string]]
+		err := arr.Scan(`{{"a}",b},{NULL,"{c,d}"}}`)
+		assert.NoError(t, err)
+		assert.Equal(t, PGArray[PGArray[string]]{
+			Some(PGArray[string]{Some("a}"), Some("b")}),
+			Some(PGArray[string]{None[string](), Some("{c,d}")}),
+		}, arr)
+	}
+}
+
+func TestPGArray_Scan_Errors(t *testing.T) {
+	var arr PGArray[int]
+
+	for _, src := range []any{
+		`1,2`,
+		`{1,2`,
+		`{1,,2}`,
+		`{1,2}x`,
+		`{"1}`,
+		`{a"b}`,
+		`{{1,2}`,
+		`[1:2]{1,2}`,
+		`{x}`,
+		int64(1),
+		nil,
+	} {
+		assert.Error(t, arr.Scan(src), src)
+	}
+}
+
+func TestPGArray_RoundTrip(t *testing.T) {
+	orig := PGArray[PGArray[string]]{
+		Some(PGArray[string]{Some(`"quoted"`), Some(`back\slash`), Some("NULL"), None[string]()}),
+		Some(PGArray[string]{Some("{}"), Some(" "), Some(""), Some("x,y")}),
+	}
+
+	v, err := orig.Value()
+	assert.NoError(t, err)
+
+	var scanned PGArray[PGArray[string]]
+	err = scanned.Scan(v)
+	assert.NoError(t, err)
+	assert.Equal(t, orig, scanned)
+}
+
+func TestPGArray_RoundTrip_Time(t *testing.T) {
+	orig := PGArray[time.Time]{
+		Some(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+		None[time.Time](),
+		Some(time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.FixedZone("", 2*3600))),
+	}
+
+	v, err := orig.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"2020-01-02 03:04:05Z",NULL,"2020-01-02 03:04:05.123456+02:00"}`, v)
+
+	var scanned PGArray[time.Time]
+	assert.NoError(t, scanned.Scan(v))
+	assert.Len(t, scanned, 3)
+	assert.True(t, orig[0].Unwrap().Equal(scanned[0].Unwrap()))
+	assert.True(t, scanned[1].IsNone())
+	assert.True(t, orig[2].Unwrap().Equal(scanned[2].Unwrap()))
+
+	// The formats PostgreSQL writes for timestamptz, timestamp and date arrays.
+	assert.NoError(t, scanned.Scan(`{"2020-01-02 03:04:05.5+02","2020-01-02 03:04:05",2020-01-02}`))
+	assert.Equal(t, PGArray[time.Time]{
+		Some(time.Date(2020, 1, 2, 3, 4, 5, 500000000, time.FixedZone("", 2*3600))),
+		Some(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Some(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+	}, scanned)
+
+	assert.Error(t, scanned.Scan(`{yesterday}`))
+}
+
+func TestPGArray_Option(t *testing.T) {
+	var o Option[PGArray[int]]
+	var s sql.Scanner = &o
+
+	err := s.Scan(`{1,NULL}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Some(PGArray[int]{Some(1), None[int]()}), o)
+
+	v, err := o.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{1,NULL}`, v)
+
+	err = s.Scan(nil)
+	assert.NoError(t, err)
+	assert.True(t, o.IsNone())
+
+	v, err = o.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
@@ -0,0 +1,103 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Optional is implemented by every Option[T]. It allows handling Options without knowing T, e.g. in serializers or loggers.
+type Optional interface {
+	// IsSome returns true if the Option has a value.
+	IsSome() bool
+	// AnyValue returns the value of the Option as an any, and whether the Option is Some.
+	AnyValue() (any, bool)
+	// ElemType returns T, the type of the value of the Option.
+	ElemType() reflect.Type
+}
+
+// OptionalPtr is implemented by every *Option[T].
+type OptionalPtr interface {
+	Optional
+	// SetAny sets the Option to Some with the given value, which must be a T.
+	// A nil value stands for the zero value of T if T is an interface, pointer, map, slice, channel or function type.
+	SetAny(v any) error
+	// Clear sets the Option to None.
+	Clear()
+}
+
+var optionalPtrType = reflect.TypeOf((*OptionalPtr)(nil)).Elem()
+
+// AnyValue returns the value of the Option as an any, and whether the Option is Some.
+func (o Option[T]) AnyValue() (any, bool) {
+	return o.value, o.isSome
+}
+
+// ElemType returns T, the type of the value of the Option.
+func (o Option[T]) ElemType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// SetAny sets the Option to Some with the given value, which must be a T.
+// A nil value stands for the zero value of T if T is an interface, pointer, map, slice, channel or function type.
+func (o *Option[T]) SetAny(v any) error {
+	if value, ok := v.(T); ok {
+		*o = Some(value)
+		return nil
+	}
+	if v == nil && isNilable(o.ElemType()) {
+		var zero T
+		*o = Some(zero)
+		return nil
+	}
+	return fmt.Errorf("cannot set %T value to Option[%s]", v, o.ElemType())
+}
+
+// Clear sets the Option to None.
+func (o *Option[T]) Clear() {
+	*o = None[T]()
+}
+
+// IsOptionType reports whether t is an Option[T] type.
+// Types embedding an Option, such as JSON[T], implement Optional too but are not Option types.
+func IsOptionType(t reflect.Type) bool {
+	return t.PkgPath() == optionalPtrType.PkgPath() && strings.HasPrefix(t.Name(), "Option[") &&
+		reflect.PointerTo(t).Implements(optionalPtrType)
+}
+
+// SomeOf returns a Some value of the Option type t holding v, which must be a value of the element type of t.
+func SomeOf(t reflect.Type, v any) (reflect.Value, error) {
+	o, err := NoneOf(t)
+	if err != nil {
+		return o, err
+	}
+	if err := o.Addr().Interface().(OptionalPtr).SetAny(v); err != nil {
+		return reflect.Value{}, err
+	}
+	return o, nil
+}
+
+// NoneOf returns a None value of the Option type t.
+func NoneOf(t reflect.Type) (reflect.Value, error) {
+	if !IsOptionType(t) {
+		return reflect.Value{}, fmt.Errorf("%s is not an Option type", t)
+	}
+	return reflect.New(t).Elem(), nil
+}
+
+// asOptionPtr returns the Option stored in v if v is an addressable Option[T] value.
+func asOptionPtr(v reflect.Value) (OptionalPtr, bool) {
+	if !v.CanAddr() || !v.Addr().CanInterface() {
+		return nil, false
+	}
+	o, ok := v.Addr().Interface().(OptionalPtr)
+	return o, ok
+}
+
+func isNilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
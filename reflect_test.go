@@ -0,0 +1,72 @@
+package opt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional(t *testing.T) {
+	var o Optional = Some(42)
+	assert.True(t, o.IsSome())
+	v, ok := o.AnyValue()
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+	assert.Equal(t, reflect.TypeOf(0), o.ElemType())
+
+	o = None[[]string]()
+	assert.False(t, o.IsSome())
+	v, ok = o.AnyValue()
+	assert.False(t, ok)
+	assert.Equal(t, []string(nil), v)
+	assert.Equal(t, reflect.TypeOf([]string{}), o.ElemType())
+}
+
+func TestOptionalPtr(t *testing.T) {
+	var i Option[int]
+	var o OptionalPtr = &i
+	assert.NoError(t, o.SetAny(3))
+	assert.Equal(t, Some(3), i)
+
+	assert.EqualError(t, o.SetAny("3"), "cannot set string value to Option[int]")
+	assert.EqualError(t, o.SetAny(nil), "cannot set <nil> value to Option[int]")
+	assert.Equal(t, Some(3), i)
+
+	o.Clear()
+	assert.Equal(t, None[int](), i)
+
+	var p Option[*int]
+	assert.NoError(t, (&p).SetAny(nil))
+	assert.Equal(t, Some[*int](nil), p)
+
+	var e Option[error]
+	assert.NoError(t, (&e).SetAny(assert.AnError))
+	assert.Equal(t, Some(assert.AnError), e)
+}
+
+func TestIsOptionType(t *testing.T) {
+	assert.True(t, IsOptionType(reflect.TypeOf(Option[int]{})))
+	assert.True(t, IsOptionType(reflect.TypeOf(Option[Option[string]]{})))
+	assert.False(t, IsOptionType(reflect.TypeOf(&Option[int]{})))
+	assert.False(t, IsOptionType(reflect.TypeOf(JSON[int]{})))
+	assert.False(t, IsOptionType(reflect.TypeOf(0)))
+}
+
+func TestSomeOf_NoneOf(t *testing.T) {
+	typ := reflect.TypeOf(Option[string]{})
+
+	v, err := SomeOf(typ, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, Some("a"), v.Interface())
+
+	v, err = NoneOf(typ)
+	assert.NoError(t, err)
+	assert.Equal(t, None[string](), v.Interface())
+
+	_, err = SomeOf(typ, 1)
+	assert.EqualError(t, err, "cannot set int value to Option[string]")
+
+	_, err = NoneOf(reflect.TypeOf(""))
+	assert.EqualError(t, err, "string is not an Option type")
+}
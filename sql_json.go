@@ -0,0 +1,93 @@
+package opt
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrJSONNull represents the error that is raised when a JSON `null` document is scanned with the JSONNullAsError policy.
+var ErrJSONNull = errors.New("json null document")
+
+// JSONNullPolicy defines how JSON.Scan handles a JSON `null` document, as opposed to a SQL NULL which is always None.
+type JSONNullPolicy int
+
+const (
+	// JSONNullAsNone scans a JSON `null` document as None, the same way as a SQL NULL. This is the default policy.
+	JSONNullAsNone JSONNullPolicy = iota
+	// JSONNullAsValue unmarshals a JSON `null` document into T, which results in Some with the zero value of T.
+	JSONNullAsValue
+	// JSONNullAsError makes Scan return ErrJSONNull on a JSON `null` document.
+	JSONNullAsError
+)
+
+// JSON is an Option whose value is stored as a JSON document in a database column (JSON, JSONB, TEXT, ...).
+// The value is marshaled and unmarshaled with encoding/json, so Option fields of T follow the same rules as Option.MarshalJSON.
+// This type implements database/sql/driver.Valuer and database/sql.Scanner.
+type JSON[T any] struct {
+	Option[T]
+
+	// NullPolicy defines how Scan handles a JSON `null` document. It is left untouched by Scan.
+	NullPolicy JSONNullPolicy
+}
+
+// SomeJSON is a function to make a JSON value with the actual value.
+func SomeJSON[T any](v T) JSON[T] {
+	return JSON[T]{Option: Some(v)}
+}
+
+// NoneJSON is a function to make a JSON value that doesn't have a value.
+func NoneJSON[T any]() JSON[T] {
+	return JSON[T]{Option: None[T]()}
+}
+
+// Scan assigns a JSON document from a database driver.
+// A SQL NULL results in None, and a JSON `null` document is handled according to NullPolicy.
+// This method is required from database/sql.Scanner interface.
+func (j *JSON[T]) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		j.Option = None[T]()
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, j)
+	}
+
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		switch j.NullPolicy {
+		case JSONNullAsNone:
+			j.Option = None[T]()
+			return nil
+		case JSONNullAsError:
+			return ErrJSONNull
+		}
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	j.Option = Some(v)
+	return nil
+}
+
+// Value returns the JSON document as a string, or nil (i.e. SQL NULL) if the value is None.
+// This method is required from database/sql/driver.Valuer interface.
+func (j JSON[T]) Value() (driver.Value, error) {
+	if j.IsNone() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(j.Unwrap())
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,146 @@
+package opt
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonDoc struct {
+	Title string         `json:"title"`
+	Tags  []string       `json:"tags"`
+	Note  Option[string] `json:"note"`
+}
+
+func TestJSON_Value(t *testing.T) {
+	v, err := SomeJSON(jsonDoc{Title: "foo", Tags: []string{"a"}}).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"title":"foo","tags":["a"],"note":null}`, v)
+
+	v, err = SomeJSON[map[string]int](nil).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `null`, v)
+
+	v, err = NoneJSON[jsonDoc]().Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	_, err = SomeJSON(make(chan int)).Value()
+	assert.Error(t, err)
+
+	var valuer driver.Valuer = SomeJSON(1)
+	assert.NotNil(t, valuer)
+}
+
+func TestJSON_Scan(t *testing.T) {
+	{
+		var j JSON[jsonDoc]
+		err := j.Scan([]byte(`{"title":"foo","note":"bar"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, SomeJSON(jsonDoc{Title: "foo", Note: Some("bar")}), j)
+
+		err = j.Scan(nil)
+		assert.NoError(t, err)
+		assert.True(t, j.IsNone())
+	}
+
+	{
+		var j JSON[[]int]
+		err := j.Scan(`[1,2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, Some([]int{1, 2}), j.Option)
+
+		err = j.Scan(int64(1))
+		assert.EqualError(t, err, "cannot scan int64 into *opt.JSON[[]int]")
+
+		err = j.Scan(`{`)
+		assert.Error(t, err)
+	}
+}
+
+func TestJSON_Scan_NullPolicy(t *testing.T) {
+	{
+		j := SomeJSON(map[string]int{"a": 1})
+		err := j.Scan(` null `)
+		assert.NoError(t, err)
+		assert.True(t, j.IsNone())
+	}
+
+	{
+		j := JSON[map[string]int]{NullPolicy: JSONNullAsValue}
+		err := j.Scan(`null`)
+		assert.NoError(t, err)
+		assert.Equal(t, Some[map[string]int](nil), j.Option)
+		assert.Equal(t, JSONNullAsValue, j.NullPolicy)
+	}
+
+	{
+		j := JSON[int]{NullPolicy: JSONNullAsError}
+		err := j.Scan([]byte(`null`))
+		assert.ErrorIs(t, err, ErrJSONNull)
+
+		err = j.Scan(nil)
+		assert.NoError(t, err)
+		assert.True(t, j.IsNone())
+	}
+}
+
+func TestJSON_MarshalJSON(t *testing.T) {
+	type payload struct {
+		Doc JSON[jsonDoc] `json:"doc"`
+	}
+
+	marshal, err := json.Marshal(payload{Doc: SomeJSON(jsonDoc{Title: "foo"})})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"doc":{"title":"foo","tags":null,"note":null}}`, string(marshal))
+
+	marshal, err = json.Marshal(payload{Doc: NoneJSON[jsonDoc]()})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"doc":null}`, string(marshal))
+}
+
+func TestJSON_SQL(t *testing.T) {
+	tmpfile, err := os.CreateTemp(os.TempDir(), "testdb")
+	assert.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", tmpfile.Name())
+	assert.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	_, err = db.Exec("CREATE TABLE test_table (id INTEGER NOT NULL PRIMARY KEY, doc TEXT);")
+	assert.NoError(t, err)
+
+	doc := jsonDoc{Title: "foo", Tags: []string{"a", "b"}, Note: Some("bar")}
+	_, err = db.Exec("INSERT INTO test_table(id, doc) VALUES (?, ?), (?, ?), (?, 'null')", 1, SomeJSON(doc), 2, NoneJSON[jsonDoc](), 3)
+	assert.NoError(t, err)
+
+	var j JSON[jsonDoc]
+
+	err = db.QueryRow("SELECT doc FROM test_table WHERE id = 1").Scan(&j)
+	assert.NoError(t, err)
+	assert.Equal(t, SomeJSON(doc), j)
+
+	var raw sql.NullString
+	err = db.QueryRow("SELECT doc FROM test_table WHERE id = 2").Scan(&raw)
+	assert.NoError(t, err)
+	assert.False(t, raw.Valid)
+
+	err = db.QueryRow("SELECT doc FROM test_table WHERE id = 2").Scan(&j)
+	assert.NoError(t, err)
+	assert.True(t, j.IsNone())
+
+	err = db.QueryRow("SELECT doc FROM test_table WHERE id = 3").Scan(&j)
+	assert.NoError(t, err)
+	assert.True(t, j.IsNone())
+
+	j.NullPolicy = JSONNullAsValue
+	err = db.QueryRow("SELECT doc FROM test_table WHERE id = 3").Scan(&j)
+	assert.NoError(t, err)
+	assert.Equal(t, Some(jsonDoc{}), j.Option)
+}
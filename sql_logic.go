@@ -0,0 +1,109 @@
+package opt
+
+import "cmp"
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is a constraint that permits any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	Integer | Float
+}
+
+// And returns the SQL three-valued logical conjunction of the given Options, where None stands for NULL (i.e. unknown).
+// This returns Some[false] if either value is Some[false] even if the other one is None, None if either value is None, and Some[true] otherwise.
+func And(a, b Option[bool]) Option[bool] {
+	if (a.IsSome() && !a.value) || (b.IsSome() && !b.value) {
+		return Some(false)
+	}
+	if a.IsNone() || b.IsNone() {
+		return None[bool]()
+	}
+	return Some(true)
+}
+
+// Or returns the SQL three-valued logical disjunction of the given Options, where None stands for NULL (i.e. unknown).
+// This returns Some[true] if either value is Some[true] even if the other one is None, None if either value is None, and Some[false] otherwise.
+// NOTE: this is unrelated to the Option.Or method, which returns a fallback value.
+func Or(a, b Option[bool]) Option[bool] {
+	if (a.IsSome() && a.value) || (b.IsSome() && b.value) {
+		return Some(true)
+	}
+	if a.IsNone() || b.IsNone() {
+		return None[bool]()
+	}
+	return Some(false)
+}
+
+// Not returns the SQL three-valued logical negation of the given Option: None stays None, and Some values are negated.
+func Not(a Option[bool]) Option[bool] {
+	if a.IsNone() {
+		return a
+	}
+	return Some(!a.value)
+}
+
+// Add returns the sum of the given Options, or None if either of them is None, like SQL's `+` operator does with NULL.
+func Add[N Number](a, b Option[N]) Option[N] {
+	if a.IsNone() || b.IsNone() {
+		return None[N]()
+	}
+	return Some(a.value + b.value)
+}
+
+// Sub returns the difference of the given Options, or None if either of them is None, like SQL's `-` operator does with NULL.
+func Sub[N Number](a, b Option[N]) Option[N] {
+	if a.IsNone() || b.IsNone() {
+		return None[N]()
+	}
+	return Some(a.value - b.value)
+}
+
+// Mul returns the product of the given Options, or None if either of them is None, like SQL's `*` operator does with NULL.
+func Mul[N Number](a, b Option[N]) Option[N] {
+	if a.IsNone() || b.IsNone() {
+		return None[N]()
+	}
+	return Some(a.value * b.value)
+}
+
+// Div returns the quotient of the given Options, or None if either of them is None, like SQL's `/` operator does with NULL.
+// A division by zero returns None too, instead of panicking for integers or giving an infinity for floats.
+func Div[N Number](a, b Option[N]) Option[N] {
+	if a.IsNone() || b.IsNone() || b.value == 0 {
+		return None[N]()
+	}
+	return Some(a.value / b.value)
+}
+
+// Compare returns -1, 0 or +1 in a Some depending on whether a is less than, equal to or greater than b, following cmp.Compare.
+// If either value is None, the comparison is unknown and this returns None, like SQL's comparison operators do with NULL.
+func Compare[T cmp.Ordered](a, b Option[T]) Option[int] {
+	if a.IsNone() || b.IsNone() {
+		return None[int]()
+	}
+	return Some(cmp.Compare(a.value, b.value))
+}
+
+// IsDistinctFrom reports whether the given Options are distinct, following SQL's `IS DISTINCT FROM` operator:
+// unlike the `<>` operator, None is considered as a regular value that is distinct from any Some value and not distinct from None.
+func IsDistinctFrom[T comparable](a, b Option[T]) bool {
+	return !IsNotDistinctFrom(a, b)
+}
+
+// IsNotDistinctFrom reports whether the given Options are equal, following SQL's `IS NOT DISTINCT FROM` operator:
+// unlike the `=` operator, two None values are equal, and None is not equal to any Some value.
+func IsNotDistinctFrom[T comparable](a, b Option[T]) bool {
+	if a.IsNone() || b.IsNone() {
+		return a.IsNone() == b.IsNone()
+	}
+	return a.value == b.value
+}
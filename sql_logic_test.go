@@ -0,0 +1,84 @@
+package opt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAndOrNot(t *testing.T) {
+	var (
+		tru  = Some(true)
+		fals = Some(false)
+		null = None[bool]()
+	)
+
+	for _, tc := range []struct {
+		a, b     Option[bool]
+		and, or  Option[bool]
+		notA     Option[bool]
+		describe string
+	}{
+		{tru, tru, tru, tru, fals, "true, true"},
+		{tru, fals, fals, tru, fals, "true, false"},
+		{tru, null, null, tru, fals, "true, null"},
+		{fals, tru, fals, tru, tru, "false, true"},
+		{fals, fals, fals, fals, tru, "false, false"},
+		{fals, null, fals, null, tru, "false, null"},
+		{null, tru, null, tru, null, "null, true"},
+		{null, fals, fals, null, null, "null, false"},
+		{null, null, null, null, null, "null, null"},
+	} {
+		assert.Equal(t, tc.and, And(tc.a, tc.b), "And(%s)", tc.describe)
+		assert.Equal(t, tc.or, Or(tc.a, tc.b), "Or(%s)", tc.describe)
+		assert.Equal(t, tc.notA, Not(tc.a), "Not(%s)", tc.describe)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	assert.Equal(t, Some(5), Add(Some(2), Some(3)))
+	assert.Equal(t, None[int](), Add(Some(2), None[int]()))
+	assert.Equal(t, None[int](), Add(None[int](), Some(3)))
+
+	assert.Equal(t, Some(-1.5), Sub(Some(1.5), Some(3.0)))
+	assert.Equal(t, None[float64](), Sub(None[float64](), Some(3.0)))
+
+	assert.Equal(t, Some[uint8](6), Mul(Some[uint8](2), Some[uint8](3)))
+	assert.Equal(t, None[uint8](), Mul(Some[uint8](2), None[uint8]()))
+
+	assert.Equal(t, Some(3), Div(Some(7), Some(2)))
+	assert.Equal(t, Some(3.5), Div(Some(7.0), Some(2.0)))
+	assert.Equal(t, None[int](), Div(Some(7), Some(0)))
+	assert.Equal(t, None[float64](), Div(Some(7.0), Some(0.0)))
+	assert.Equal(t, None[int](), Div(None[int](), Some(2)))
+
+	type Cents int64
+	assert.Equal(t, Some[Cents](150), Add(Some[Cents](100), Some[Cents](50)))
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, Some(-1), Compare(Some(1), Some(2)))
+	assert.Equal(t, Some(0), Compare(Some(2), Some(2)))
+	assert.Equal(t, Some(1), Compare(Some(3.5), Some(2.0)))
+	assert.Equal(t, Some(-1), Compare(Some(math.NaN()), Some(2.0)))
+	assert.Equal(t, Some(1), Compare(Some("b"), Some("a")))
+	assert.Equal(t, None[int](), Compare(None[int](), Some(2)))
+	assert.Equal(t, None[int](), Compare(None[int](), None[int]()))
+}
+
+func TestIsDistinctFrom(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     Option[int]
+		distinct bool
+	}{
+		{Some(1), Some(1), false},
+		{Some(1), Some(2), true},
+		{Some(0), None[int](), true},
+		{None[int](), Some(0), true},
+		{None[int](), None[int](), false},
+	} {
+		assert.Equal(t, tc.distinct, IsDistinctFrom(tc.a, tc.b), "%s, %s", tc.a, tc.b)
+		assert.Equal(t, !tc.distinct, IsNotDistinctFrom(tc.a, tc.b), "%s, %s", tc.a, tc.b)
+	}
+}
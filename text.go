@@ -0,0 +1,101 @@
+package opt
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+)
+
+// parseText parses the textual representation of a value of type t, as found in environment variables, flags or struct tags.
+// Types implementing encoding.TextUnmarshaler are parsed with it; time.Duration, url.URL, booleans and numbers are parsed with their usual parsers.
+// The elements of slices (other than []byte) are separated by sep.
+func parseText(s string, t reflect.Type, sep string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		return v, err
+	}
+
+	switch t {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		v.SetInt(int64(d))
+		return v, err
+	case urlType:
+		u, err := url.Parse(s)
+		if err != nil {
+			return v, err
+		}
+		v.Set(reflect.ValueOf(*u))
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 0, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		elem, err := parseText(s, t.Elem(), sep)
+		if err != nil {
+			return v, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		v.Set(ptr)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(s))
+			break
+		}
+		if s == "" {
+			v.Set(reflect.MakeSlice(t, 0, 0))
+			break
+		}
+		parts := strings.Split(s, sep)
+		v.Set(reflect.MakeSlice(t, len(parts), len(parts)))
+		for i, part := range parts {
+			elem, err := parseText(strings.TrimSpace(part), t.Elem(), sep)
+			if err != nil {
+				return v, fmt.Errorf("element %d: %w", i, err)
+			}
+			v.Index(i).Set(elem)
+		}
+	default:
+		return v, fmt.Errorf("unsupported type %s", t)
+	}
+	return v, nil
+}
@@ -0,0 +1,42 @@
+package opt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseText(t *testing.T) {
+	for _, tc := range []struct {
+		s        string
+		expected any
+	}{
+		{"foo", "foo"},
+		{"0x10", 16},
+		{"-8", int8(-8)},
+		{"42", uint(42)},
+		{"1.5", float32(1.5)},
+		{"t", true},
+		{"2s", 2 * time.Second},
+		{"raw", []byte("raw")},
+		{"", []string{}},
+		{"1;2", []int{1, 2}},
+		{"2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	} {
+		v, err := parseText(tc.s, reflect.TypeOf(tc.expected), ";")
+		assert.NoError(t, err, tc.s)
+		assert.Equal(t, tc.expected, v.Interface(), tc.s)
+	}
+
+	v, err := parseText("7", reflect.TypeOf((*int)(nil)), ",")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, *v.Interface().(*int))
+
+	_, err = parseText("300", reflect.TypeOf(uint8(0)), ",")
+	assert.Error(t, err)
+
+	_, err = parseText("x", reflect.TypeOf(map[string]int{}), ",")
+	assert.EqualError(t, err, "unsupported type map[string]int")
+}
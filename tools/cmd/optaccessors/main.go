@@ -0,0 +1,20 @@
+// Command optaccessors generates, for the pointer fields of struct types, GetFooOpt and SetFoo methods taking and
+// returning opt.Option values instead of pointers. The methods are safe to call on nil receivers.
+//
+// Usage:
+//
+//	optaccessors -type User,Post [-output user_accessors.go] [dir]
+//
+// It's meant to be run by go generate, from a directive in the package declaring the types:
+//
+//	//go:generate go run github.com/shimmerglass/go-optional/tools/cmd/optaccessors -type User
+//
+// The file is written to the package directory, dir defaulting to the current directory, and is named after the first
+// type by default. See optgen.Accessors for the generated code.
+package main
+
+import "github.com/shimmerglass/go-optional/tools/optgen"
+
+func main() {
+	optgen.Main("optaccessors", "_accessors.go", optgen.Accessors)
+}
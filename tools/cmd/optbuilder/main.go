@@ -0,0 +1,21 @@
+// Command optbuilder generates, for struct types, the XxxBuilder types building their values: the fields that aren't
+// opt.Option values are required, and Build returns an error if one of them isn't set, while the Option fields are
+// optional and default to None.
+//
+// Usage:
+//
+//	optbuilder -type User,Post [-output user_builder.go] [dir]
+//
+// It's meant to be run by go generate, from a directive in the package declaring the types:
+//
+//	//go:generate go run github.com/shimmerglass/go-optional/tools/cmd/optbuilder -type User
+//
+// The file is written to the package directory, dir defaulting to the current directory, and is named after the first
+// type by default. See optgen.Builder for the generated code.
+package main
+
+import "github.com/shimmerglass/go-optional/tools/optgen"
+
+func main() {
+	optgen.Main("optbuilder", "_builder.go", optgen.Builder)
+}
@@ -0,0 +1,137 @@
+// Command optddl prints the CREATE TABLE statements of Go struct types, with opt.Option fields as nullable columns.
+//
+// Usage:
+//
+//	optddl -type User,Post [-dialect sqlite|postgres|mysql] [-diff app.db] [packages]
+//
+// The tables are named after the snake-cased type names. See the ddl package for the column naming and tag options.
+// With -diff, the statements that migrate the given SQLite database to the struct definitions are printed instead.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"go/types"
+	"os"
+	"strings"
+	"unicode"
+
+	_ "github.com/mattn/go-sqlite3"
+	opt "github.com/shimmerglass/go-optional"
+	"github.com/shimmerglass/go-optional/ddl"
+	"github.com/shimmerglass/go-optional/tools/internal/loader"
+)
+
+func main() {
+	dialectName := flag.String("dialect", "sqlite", "SQL dialect: sqlite, postgres or mysql")
+	typeNames := flag.String("type", "", "comma-separated list of struct type names")
+	diffDB := flag.String("diff", "", "path of a SQLite database to diff the tables against")
+	flag.Parse()
+
+	if err := run(*dialectName, *typeNames, *diffDB, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "optddl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dialectName, typeNames, diffDB string, patterns []string) error {
+	if typeNames == "" {
+		return fmt.Errorf("-type is required")
+	}
+	dialect, err := parseDialect(dialectName)
+	if err != nil {
+		return err
+	}
+	if diffDB != "" && dialect != opt.DialectSQLite {
+		return fmt.Errorf("-diff requires the %s dialect", opt.DialectSQLite)
+	}
+	if diffDB != "" {
+		// sql.Open would create a missing database, and the diff would then create every table.
+		if _, err := os.Stat(diffDB); err != nil {
+			return err
+		}
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	pkgs, err := loader.Load("", patterns...)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, name := range strings.Split(typeNames, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	structs, err := loader.Structs(pkgs, names)
+	if err != nil {
+		return err
+	}
+
+	var tables []ddl.Table
+	for _, named := range structs {
+		name := named.Obj().Name()
+		table, err := ddl.FromTypesStruct(dialect, snakeCase(name), named.Underlying().(*types.Struct))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", named.Obj().Pkg().Path(), name, err)
+		}
+		tables = append(tables, table)
+	}
+
+	var stmts []string
+	if diffDB != "" {
+		db, err := sql.Open("sqlite3", diffDB)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = db.Close()
+		}()
+
+		for _, table := range tables {
+			diff, err := ddl.DiffSQLite(context.Background(), db, table)
+			if err != nil {
+				return err
+			}
+			stmts = append(stmts, diff...)
+		}
+	} else {
+		for _, table := range tables {
+			stmts = append(stmts, table.CreateSQL())
+		}
+	}
+
+	for _, stmt := range stmts {
+		fmt.Println(stmt)
+	}
+	return nil
+}
+
+func parseDialect(name string) (opt.Dialect, error) {
+	for _, d := range []opt.Dialect{opt.DialectSQLite, opt.DialectPostgres, opt.DialectMySQL} {
+		if d.String() == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown dialect %q", name)
+}
+
+// snakeCase converts a Go identifier to snake case, e.g. "HTTPRequestLog" to "http_request_log".
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"User":           "user",
+		"UserAccount":    "user_account",
+		"HTTPRequestLog": "http_request_log",
+		"APIKey":         "api_key",
+		"UserID":         "user_id",
+		"ID":             "id",
+	} {
+		assert.Equal(t, expected, snakeCase(name))
+	}
+}
+
+func TestRun_TypeRequired(t *testing.T) {
+	assert.EqualError(t, run("sqlite", "", "", nil), "-type is required")
+}
+
+func TestRun_DiffMissingDatabase(t *testing.T) {
+	err := run("sqlite", "User", "testdata/missing.db", nil)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoFileExists(t, "testdata/missing.db")
+}
@@ -0,0 +1,107 @@
+// Command optmigrate rewrites struct fields of pointer types *T to opt.Option[T], along with the code using them.
+//
+// Usage:
+//
+//	optmigrate -fields User,Post.Title [-d] [packages]
+//
+// Fields are selected as Type.Field, or Type for all the pointer fields of a struct type. The packages are loaded with
+// their tests. See the optmigrate package for the rewritten uses. The uses that cannot be rewritten safely are reported
+// on stderr, and left unchanged.
+// With -d, the changes are printed as a unified diff instead of being written to the files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/shimmerglass/go-optional/tools/internal/loader"
+	"github.com/shimmerglass/go-optional/tools/optmigrate"
+)
+
+func main() {
+	fields := flag.String("fields", "", "comma-separated list of the fields to migrate, as Type.Field or Type")
+	diff := flag.Bool("d", false, "print a diff of the changes instead of writing them")
+	flag.Parse()
+
+	if err := run(os.Stdout, os.Stderr, *fields, *diff, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "optmigrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(stdout, stderr io.Writer, fields string, diff bool, patterns []string) error {
+	if fields == "" {
+		return fmt.Errorf("-fields is required")
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	pkgs, err := loader.LoadWithTests("", patterns...)
+	if err != nil {
+		return err
+	}
+
+	var selected []string
+	for _, f := range strings.Split(fields, ",") {
+		selected = append(selected, strings.TrimSpace(f))
+	}
+	res, err := optmigrate.Migrate(pkgs, selected)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(res.Files))
+	for name := range res.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !diff {
+			if err := os.WriteFile(name, res.Files[name], 0o644); err != nil {
+				return err
+			}
+			continue
+		}
+
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		rel := relativePath(name)
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(src)),
+			B:        difflib.SplitLines(string(res.Files[name])),
+			FromFile: "a/" + rel,
+			ToFile:   "b/" + rel,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(stdout, text)
+	}
+
+	for _, issue := range res.Issues {
+		issue.Pos.Filename = relativePath(issue.Pos.Filename)
+		fmt.Fprintln(stderr, issue)
+	}
+	return nil
+}
+
+func relativePath(name string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, name); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return name
+}
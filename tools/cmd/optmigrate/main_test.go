@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const migrated = `package p
+
+import opt "github.com/shimmerglass/go-optional"
+
+type User struct {
+	Name opt.Option[string]
+}
+
+func Name(u User) string {
+	if u.Name.IsNone() {
+		return ""
+	}
+	return u.Name.Unwrap()
+}
+
+func Ptr(u User) *string {
+	return u.Name
+}
+`
+
+func TestRun(t *testing.T) {
+	var stdout, stderr strings.Builder
+	err := run(&stdout, &stderr, "User", true, []string{"./testdata/p"})
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "--- a/testdata/p/p.go\n+++ b/testdata/p/p.go\n")
+	assert.Contains(t, stdout.String(), "-\tName *string\n+\tName opt.Option[string]\n")
+	assert.Equal(t, "testdata/p/p.go:15:9: u.Name is used as a pointer, rewrite it by hand, e.g. with UnwrapAsPtr\n", stderr.String())
+
+	// The packages must be in the module to be loaded, so the copy goes to testdata rather than to a temporary directory.
+	dir, err := os.MkdirTemp("testdata", "write")
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	src, err := os.ReadFile("testdata/p/p.go")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "p.go"), src, 0o644))
+
+	stdout.Reset()
+	stderr.Reset()
+	err = run(&stdout, &stderr, "User.Name", false, []string{"./" + dir})
+	assert.NoError(t, err)
+	assert.Empty(t, stdout.String())
+	written, err := os.ReadFile(filepath.Join(dir, "p.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, migrated, string(written))
+}
+
+func TestRun_Errors(t *testing.T) {
+	var stdout, stderr strings.Builder
+	assert.EqualError(t, run(&stdout, &stderr, "", true, nil), "-fields is required")
+	assert.EqualError(t, run(&stdout, &stderr, "User.Missing", true, []string{"./testdata/p"}), "no pointer field matches User.Missing")
+}
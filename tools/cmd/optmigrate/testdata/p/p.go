@@ -0,0 +1,16 @@
+package p
+
+type User struct {
+	Name *string
+}
+
+func Name(u User) string {
+	if u.Name == nil {
+		return ""
+	}
+	return *u.Name
+}
+
+func Ptr(u User) *string {
+	return u.Name
+}
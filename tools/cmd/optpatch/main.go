@@ -0,0 +1,20 @@
+// Command optpatch generates, for struct types, the XxxPatch types holding partial updates of them, with Apply,
+// IsEmpty and Merge methods and an XxxPatchFromDiff constructor.
+//
+// Usage:
+//
+//	optpatch -type User,Post [-output user_patch.go] [dir]
+//
+// It's meant to be run by go generate, from a directive in the package declaring the types:
+//
+//	//go:generate go run github.com/shimmerglass/go-optional/tools/cmd/optpatch -type User
+//
+// The file is written to the package directory, dir defaulting to the current directory, and is named after the first
+// type by default. See optgen.Patch for the generated code.
+package main
+
+import "github.com/shimmerglass/go-optional/tools/optgen"
+
+func main() {
+	optgen.Main("optpatch", "_patch.go", optgen.Patch)
+}
@@ -0,0 +1,70 @@
+// Command optts generates the TypeScript interfaces of the JSON encoding of the struct types of Go packages, for the
+// clients of an API to share its payload types. opt.Option fields are "field: T | null", or "field?: T" with the
+// omitzero option of their json tag in modules requiring Go 1.24 or later.
+//
+// Usage:
+//
+//	optts [-type User,Post] [-o api.ts] [packages]
+//
+// The interfaces of the exported struct types of the packages are generated by default, or of the types selected with
+// -type, along with the struct types they refer to. See optgen.TypeScript for the encoding rules.
+// The output is written to stdout, or to the file given with -o.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shimmerglass/go-optional/tools/internal/loader"
+	"github.com/shimmerglass/go-optional/tools/optgen"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct type names, defaults to all exported struct types")
+	output := flag.String("o", "", "output file, defaults to stdout")
+	flag.Parse()
+
+	if err := run(os.Stdout, *typeNames, *output, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "optts:", err)
+		os.Exit(1)
+	}
+}
+
+func run(stdout io.Writer, typeNames, output string, patterns []string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := loader.Load("", patterns...)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if typeNames != "" {
+		for _, name := range strings.Split(typeNames, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	roots, err := loader.Structs(pkgs, names)
+	if err != nil {
+		return err
+	}
+
+	// The JSON is encoded by the module of the packages, whose Go version tells whether omitzero is honored.
+	goVersion := ""
+	if m := pkgs[0].Module; m != nil {
+		goVersion = m.GoVersion
+	}
+	src, err := optgen.TypeScript(roots, goVersion)
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		return os.WriteFile(output, src, 0o644)
+	}
+	_, err = stdout.Write(src)
+	return err
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var out strings.Builder
+	assert.NoError(t, run(&out, "Comment", "", []string{"./testdata/api"}))
+	assert.Equal(t, `// Code generated by optts; DO NOT EDIT.
+
+export interface Comment {
+  id: number;
+  body: string;
+  edited_at: string | null;
+  author: Author | null;
+}
+
+export interface Author {
+  name: string;
+}
+`, out.String())
+
+	output := filepath.Join(t.TempDir(), "api.ts")
+	assert.NoError(t, run(&out, "", output, []string{"./testdata/api"}))
+	src, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "export interface Author {\n  name: string;\n}\n\nexport interface Comment {\n")
+	assert.NotContains(t, string(src), "page")
+
+	err = run(&out, "Comment,Missing", "", []string{"./testdata/api"})
+	assert.EqualError(t, err, "struct types not found: Missing")
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"time"
+
+	opt "github.com/shimmerglass/go-optional"
+)
+
+type Comment struct {
+	ID       int64                 `json:"id"`
+	Body     string                `json:"body"`
+	EditedAt opt.Option[time.Time] `json:"edited_at,omitzero"`
+	Author   opt.Option[Author]    `json:"author"`
+}
+
+type Author struct {
+	Name string `json:"name"`
+}
+
+type page struct {
+	Comments []Comment
+}
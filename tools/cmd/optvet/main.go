@@ -0,0 +1,129 @@
+// Command optvet reports misuses of opt.Option values, such as calls to Unwrap that no IsSome check guards.
+//
+// Usage:
+//
+//	optvet [-fix] [packages]
+//
+// See the optvet package for the list of checks. With -fix, the suggested fixes are applied to the source files.
+// The exit status is 3 if misuses are reported and not fixed, like the other go/analysis drivers.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/shimmerglass/go-optional/tools/internal/loader"
+	"github.com/shimmerglass/go-optional/tools/optvet"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "apply the suggested fixes")
+	flag.Parse()
+
+	n, err := run(os.Stdout, *fix, flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "optvet:", err)
+		os.Exit(1)
+	}
+	if n > 0 && !*fix {
+		os.Exit(3)
+	}
+}
+
+// run prints the diagnostics of the packages matching the patterns to w, applies their fixes if fix is set,
+// and returns the number of diagnostics.
+func run(w io.Writer, fix bool, patterns []string) (int, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := loader.Load("", patterns...)
+	if err != nil {
+		return 0, err
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{optvet.Analyzer}, pkgs, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	var fset *token.FileSet
+	var edits []analysis.TextEdit
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return n, act.Err
+		}
+		fset = act.Package.Fset
+		for _, d := range act.Diagnostics {
+			n++
+			fmt.Fprintf(w, "%s: %s\n", relativePosition(fset.Position(d.Pos)), d.Message)
+			if len(d.SuggestedFixes) > 0 {
+				edits = append(edits, d.SuggestedFixes[0].TextEdits...)
+			}
+		}
+	}
+
+	if fix && len(edits) > 0 {
+		return n, applyEdits(fset, edits)
+	}
+	return n, nil
+}
+
+func relativePosition(pos token.Position) token.Position {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, pos.Filename); err == nil && filepath.IsLocal(rel) {
+			pos.Filename = rel
+		}
+	}
+	return pos
+}
+
+// applyEdits applies the edits to the files they belong to, and formats them.
+// An edit overlapping a previous one in the same file, e.g. from another diagnostic about the same expression, is skipped.
+func applyEdits(fset *token.FileSet, edits []analysis.TextEdit) error {
+	byFile := map[string][]analysis.TextEdit{}
+	for _, e := range edits {
+		name := fset.File(e.Pos).Name()
+		byFile[name] = append(byFile[name], e)
+	}
+
+	for name, edits := range byFile {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		file := fset.File(edits[0].Pos)
+		sort.SliceStable(edits, func(i, j int) bool { return edits[i].Pos < edits[j].Pos })
+
+		var out []byte
+		last := 0
+		for _, e := range edits {
+			start, end := file.Offset(e.Pos), file.Offset(e.End)
+			if start < last {
+				continue
+			}
+			out = append(out, src[last:start]...)
+			out = append(out, e.NewText...)
+			last = end
+		}
+		out = append(out, src[last:]...)
+
+		formatted, err := format.Source(out)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := os.WriteFile(name, formatted, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
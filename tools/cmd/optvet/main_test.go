@@ -0,0 +1,49 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestRun(t *testing.T) {
+	var out strings.Builder
+	n, err := run(&out, false, []string{"./testdata/p"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "testdata/p/p.go:8:9: Unwrap called on age without checking IsSome: it gives the zero value for None\n", out.String())
+
+	_, err = run(&out, false, []string{"./testdata/missing"})
+	assert.Error(t, err)
+}
+
+func TestApplyEdits(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "a.go")
+	src := "package a\n\nfunc f(x int) int { return x.Unwrap() }\n"
+	assert.NoError(t, os.WriteFile(name, []byte(src), 0o644))
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, src, 0)
+	assert.NoError(t, err)
+	edit := func(old, new string) analysis.TextEdit {
+		start := f.FileStart + token.Pos(strings.Index(src, old))
+		return analysis.TextEdit{Pos: start, End: start + token.Pos(len(old)), NewText: []byte(new)}
+	}
+
+	err = applyEdits(fset, []analysis.TextEdit{
+		edit("Unwrap()", "TakeOr(0)"),
+		edit("Unwrap", "Overlapping"),
+		edit("x int", "x   opt.Option[int]"),
+	})
+	assert.NoError(t, err)
+
+	fixed, err := os.ReadFile(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "package a\n\nfunc f(x opt.Option[int]) int { return x.TakeOr(0) }\n", string(fixed))
+}
@@ -0,0 +1,16 @@
+package p
+
+import (
+	opt "github.com/shimmerglass/go-optional"
+)
+
+func Age(age opt.Option[int]) int {
+	return age.Unwrap()
+}
+
+func Name(name opt.Option[string]) string {
+	if name.IsNone() {
+		return "anonymous"
+	}
+	return name.Unwrap()
+}
@@ -0,0 +1,21 @@
+module github.com/shimmerglass/go-optional/tools
+
+go 1.22.0
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/shimmerglass/go-optional v0.0.0
+	github.com/stretchr/testify v1.9.0
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The tools are built against the library of the same checkout.
+replace github.com/shimmerglass/go-optional => ../
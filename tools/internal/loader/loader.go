@@ -0,0 +1,111 @@
+// Package loader loads and type-checks the packages the tools of this module work on.
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadMode is the go/packages mode used by the tools of this module.
+// Packages are type-checked from source, dependencies included, so that the tools don't depend on the export data format of the Go toolchain.
+const LoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+	packages.NeedTypes | packages.NeedTypesSizes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedModule
+
+// Load loads and type-checks the packages matching the patterns, relative to dir.
+// This returns an error if any package, or one of its dependencies, has errors.
+func Load(dir string, patterns ...string) ([]*packages.Package, error) {
+	return load(&packages.Config{Mode: LoadMode, Dir: dir}, patterns)
+}
+
+// LoadWithTests does the same as Load, and also loads the test variants of the packages, which include their _test.go files.
+// A file can then belong to several of the returned packages.
+func LoadWithTests(dir string, patterns ...string) ([]*packages.Package, error) {
+	return load(&packages.Config{Mode: LoadMode, Dir: dir, Tests: true}, patterns)
+}
+
+// LoadIgnoring does the same as Load, ignoring the content of the given Go files but their package clause, so that the
+// outdated files a generator is about to overwrite don't prevent loading their package. Missing files are skipped.
+func LoadIgnoring(dir string, ignored []string, patterns ...string) ([]*packages.Package, error) {
+	overlay := map[string][]byte{}
+	for _, name := range ignored {
+		f, err := parser.ParseFile(token.NewFileSet(), name, nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+		overlay[abs] = []byte("package " + f.Name.Name + "\n")
+	}
+	return load(&packages.Config{Mode: LoadMode, Dir: dir, Overlay: overlay}, patterns)
+}
+
+// Structs returns the named struct types of pkgs with the given names, or all their exported struct types if names is empty.
+func Structs(pkgs []*packages.Package, names []string) ([]*types.Named, error) {
+	var wanted map[string]bool
+	if len(names) > 0 {
+		wanted = map[string]bool{}
+		for _, name := range names {
+			wanted[name] = true
+		}
+	}
+
+	var structs []*types.Named
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() || (wanted == nil && !obj.Exported()) || (wanted != nil && !wanted[name]) {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			if _, ok := named.Underlying().(*types.Struct); !ok {
+				continue
+			}
+			delete(wanted, name)
+			structs = append(structs, named)
+		}
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for name := range wanted {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("struct types not found: %s", strings.Join(missing, ", "))
+	}
+	return structs, nil
+}
+
+func load(cfg *packages.Config, patterns []string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages matching %s", strings.Join(patterns, " "))
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,60 @@
+package loader
+
+import (
+	"go/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadWithTests(t *testing.T) {
+	pkgs, err := LoadWithTests("testdata/p", ".")
+	assert.NoError(t, err)
+
+	var files []string
+	for _, pkg := range pkgs {
+		for _, f := range pkg.GoFiles {
+			files = append(files, filepath.Base(f))
+		}
+	}
+	assert.Contains(t, files, "p_test.go")
+}
+
+func TestLoadIgnoring(t *testing.T) {
+	_, err := Load("testdata/gen", ".")
+	assert.Error(t, err)
+
+	pkgs, err := LoadIgnoring("testdata/gen", []string{"testdata/gen/user_gen.go", "testdata/gen/missing.go"}, ".")
+	assert.NoError(t, err)
+	assert.NotNil(t, pkgs[0].Types.Scope().Lookup("User"))
+}
+
+func TestStructs(t *testing.T) {
+	pkgs, err := Load("testdata/p", ".")
+	assert.NoError(t, err)
+
+	names := func(structs []*types.Named) []string {
+		var names []string
+		for _, named := range structs {
+			names = append(names, named.Obj().Name())
+		}
+		return names
+	}
+
+	structs, err := Structs(pkgs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Option", "S"}, names(structs))
+
+	structs, err = Structs(pkgs, []string{"S", "unexported"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"S", "unexported"}, names(structs))
+
+	_, err = Structs(pkgs, []string{"S", "Name", "Missing"})
+	assert.EqualError(t, err, "struct types not found: Missing, Name")
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load("testdata", "./missing")
+	assert.Error(t, err)
+}
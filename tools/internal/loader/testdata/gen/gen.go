@@ -0,0 +1,5 @@
+package gen
+
+type User struct {
+	Name string
+}
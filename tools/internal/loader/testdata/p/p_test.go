@@ -0,0 +1,7 @@
+package p
+
+import "testing"
+
+func TestS(t *testing.T) {
+	_ = S{D: "d"}
+}
@@ -0,0 +1,44 @@
+package optgen
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// Accessors generates, for each exported field Foo of type *T of the struct type Xxx, a GetFooOpt method returning it
+// as an Option[T], with FromNillable semantics, and a SetFoo method taking an Option[T], None setting the field to nil.
+// Both are safe to call on a nil *Xxx: GetFooOpt returns None and SetFoo does nothing.
+// The methods must be generated in the package declaring Xxx, so the types of other packages, such as third-party SDKs,
+// can't get them.
+func Accessors(f *File, named *types.Named) error {
+	name := named.Obj().Name()
+	_, args := f.TypeParams(named)
+
+	n := 0
+	for _, field := range Fields(named) {
+		p, ok := types.Unalias(field.Type()).(*types.Pointer)
+		if !ok {
+			continue
+		}
+		n++
+		getter, setter := "Get"+field.Name()+"Opt", "Set"+field.Name()
+		if err := f.checkNoMember(named, getter, setter); err != nil {
+			return err
+		}
+		elem := f.Type(p.Elem())
+
+		f.Printf("// %s returns the %s field of x, or None if it or x is nil.\n", getter, field.Name())
+		f.Printf("func (x *%s%s) %s() %s[%s] {\n", name, args, getter, f.Opt("Option"), elem)
+		f.Printf("\tif x == nil {\n\t\treturn %s[%s]()\n\t}\n", f.Opt("None"), elem)
+		f.Printf("\treturn %s(x.%s)\n}\n\n", f.Opt("FromNillable"), field.Name())
+
+		f.Printf("// %s sets the %s field of x to a pointer to the value of v, or to nil if v is None. It does nothing if x is nil.\n", setter, field.Name())
+		f.Printf("func (x *%s%s) %s(v %s[%s]) {\n", name, args, setter, f.Opt("Option"), elem)
+		f.Printf("\tif x == nil {\n\t\treturn\n\t}\n")
+		f.Printf("\tx.%s = v.UnwrapAsPtr()\n}\n\n", field.Name())
+	}
+	if n == 0 {
+		return fmt.Errorf("%s has no exported pointer fields", name)
+	}
+	return nil
+}
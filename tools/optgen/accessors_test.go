@@ -0,0 +1,21 @@
+package optgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessors(t *testing.T) {
+	generate(t, "optaccessors", "accessors.golden", Accessors, "Post", "Node")
+}
+
+func TestAccessors_Errors(t *testing.T) {
+	pkg := loadModels(t)
+	structs, err := LookupStructs(pkg, []string{"Counter", "User"})
+	assert.NoError(t, err)
+
+	f := NewFile("optaccessors", pkg)
+	assert.EqualError(t, Accessors(f, structs[0]), "Counter.SetCount is already declared")
+	assert.EqualError(t, Accessors(f, structs[1]), "User has no exported pointer fields")
+}
@@ -0,0 +1,104 @@
+package optgen
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+)
+
+// Builder generates the XxxBuilder type building values of the struct type Xxx, with its NewXxxBuilder constructor.
+// The fields that aren't Options are required: each gets a setter named after it, and Build returns an error if one of
+// them isn't set. The Option[T] fields are optional: each gets a WithFoo setter taking a T, and is None if not set.
+// Options are recognized by the import path of the opt package, whatever the name it's imported under.
+// Unexported fields get setters too, so that types keeping their fields unexported to be immutable can be built.
+func Builder(f *File, named *types.Named) error {
+	name := named.Obj().Name()
+	builder := name + "Builder"
+	decl, args := f.TypeParams(named)
+	if err := f.checkUndeclared(builder, "New"+builder); err != nil {
+		return err
+	}
+
+	type builderField struct {
+		name, method string
+		typ          types.Type
+		optional     bool
+	}
+	var fields []builderField
+	var required []string
+	methods := map[string]bool{"Build": true}
+	st := named.Underlying().(*types.Struct)
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		if v.Name() == "_" {
+			continue
+		}
+		field := builderField{name: v.Name(), method: exportedName(v.Name()), typ: v.Type()}
+		if elem, ok := opttypes.OptionElem(v.Type()); ok {
+			field.method, field.typ, field.optional = "With"+field.method, elem, true
+		} else {
+			required = append(required, strconv.Quote(v.Name()))
+		}
+		if methods[field.method] {
+			return fmt.Errorf("the %s setter of %s.%s conflicts with another method of %s", field.method, name, v.Name(), builder)
+		}
+		methods[field.method] = true
+		fields = append(fields, field)
+	}
+
+	f.Printf("// %s builds %s values.", builder, name)
+	if len(required) > 0 {
+		f.Printf(" Its required fields must all be set before calling Build.")
+	}
+	f.Printf("\n")
+	f.Printf("type %s%s struct {\n\tv %s%s\n", builder, decl, name, args)
+	if len(required) > 0 {
+		f.Printf("\tset [%d]bool\n", len(required))
+	}
+	f.Printf("}\n\n")
+
+	f.Printf("// New%s returns an empty %s.\n", builder, builder)
+	f.Printf("func New%s%s() *%s%s {\n\treturn &%s%s{}\n}\n\n", builder, decl, builder, args, builder, args)
+
+	n := 0
+	for _, field := range fields {
+		if field.optional {
+			f.Printf("// %s sets the optional %s field, None by default.\n", field.method, field.name)
+		} else {
+			f.Printf("// %s sets the required %s field.\n", field.method, field.name)
+		}
+		f.Printf("func (b *%s%s) %s(v %s) *%s%s {\n", builder, args, field.method, f.Type(field.typ), builder, args)
+		if field.optional {
+			f.Printf("\tb.v.%s = %s(v)\n", field.name, f.Opt("Some"))
+		} else {
+			f.Printf("\tb.v.%s = v\n\tb.set[%d] = true\n", field.name, n)
+			n++
+		}
+		f.Printf("\treturn b\n}\n\n")
+	}
+
+	f.Printf("// Build returns the built %s, or an error listing the required fields that aren't set.\n", name)
+	f.Printf("func (b *%s%s) Build() (%s%s, error) {\n", builder, args, name, args)
+	if len(required) > 0 {
+		f.Printf("\tvar missing []string\n")
+		f.Printf("\tfor i, name := range [...]string{%s} {\n", strings.Join(required, ", "))
+		f.Printf("\t\tif !b.set[i] {\n\t\t\tmissing = append(missing, name)\n\t\t}\n\t}\n")
+		f.Printf("\tif len(missing) > 0 {\n")
+		f.Printf("\t\treturn %s%s{}, %s(\"cannot build %s, missing required fields: %%s\", %s(missing, \", \"))\n",
+			name, args, f.Qualify("fmt", "fmt", "Errorf"), name, f.Qualify("strings", "strings", "Join"))
+		f.Printf("\t}\n")
+	}
+	f.Printf("\treturn b.v, nil\n}\n\n")
+	return nil
+}
+
+// exportedName returns name with its first letter in upper case.
+func exportedName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
@@ -0,0 +1,20 @@
+package optgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	generate(t, "optbuilder", "builder.golden", Builder, "Order", "Pair", "Timestamps")
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	pkg := loadModels(t)
+	structs, err := LookupStructs(pkg, []string{"Account"})
+	assert.NoError(t, err)
+
+	err = Builder(NewFile("optbuilder", pkg), structs[0])
+	assert.EqualError(t, err, "the Name setter of Account.name conflicts with another method of AccountBuilder")
+}
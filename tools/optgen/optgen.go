@@ -0,0 +1,307 @@
+// Package optgen generates code for the struct types of a package, for the generator commands of this module.
+package optgen
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+	"github.com/shimmerglass/go-optional/tools/internal/loader"
+)
+
+// File is a Go source file being generated into a package.
+type File struct {
+	pkg  *types.Package
+	tool string
+	// imports holds the names of the imported packages by import path, and paths the import paths by name.
+	imports map[string]string
+	paths   map[string]string
+	body    bytes.Buffer
+}
+
+// NewFile returns an empty file of pkg, generated by the given tool.
+func NewFile(tool string, pkg *types.Package) *File {
+	return &File{pkg: pkg, tool: tool, imports: map[string]string{}, paths: map[string]string{}}
+}
+
+// Printf appends formatted code to the file.
+func (f *File) Printf(format string, args ...any) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+// Import returns the name the package at path is known as in the file, importing it under name, or a variant of name
+// if another package already uses it. This returns "" for the package of the file itself.
+func (f *File) Import(path, name string) string {
+	if path == f.pkg.Path() {
+		return ""
+	}
+	if n, ok := f.imports[path]; ok {
+		return n
+	}
+	n := name
+	for i := 2; f.paths[n] != "" || f.pkg.Scope().Lookup(n) != nil; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	f.imports[path], f.paths[n] = n, path
+	return n
+}
+
+// Qualify returns member of the package at path as written in the file, importing the package under name if needed.
+func (f *File) Qualify(path, name, member string) string {
+	if n := f.Import(path, name); n != "" {
+		return n + "." + member
+	}
+	return member
+}
+
+// Opt returns member of the opt package as written in the file, e.g. "opt.Option".
+func (f *File) Opt(member string) string {
+	return f.Qualify(opttypes.PkgPath, "opt", member)
+}
+
+// Type returns t as written in the file, importing the packages it refers to.
+func (f *File) Type(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return f.Import(pkg.Path(), pkg.Name())
+	})
+}
+
+// TypeParams returns the type parameter list of named as declared, e.g. "[K comparable, V any]", and as used in the
+// type instantiated with its own parameters, e.g. "[K, V]". Both are "" if named isn't generic.
+func (f *File) TypeParams(named *types.Named) (decl, args string) {
+	tparams := named.TypeParams()
+	if tparams.Len() == 0 {
+		return "", ""
+	}
+	decls := make([]string, tparams.Len())
+	names := make([]string, tparams.Len())
+	for i := range decls {
+		tp := tparams.At(i)
+		names[i] = tp.Obj().Name()
+		decls[i] = names[i] + " " + f.Type(tp.Constraint())
+	}
+	return "[" + strings.Join(decls, ", ") + "]", "[" + strings.Join(names, ", ") + "]"
+}
+
+// Bytes returns the formatted source of the file.
+func (f *File) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by %s; DO NOT EDIT.\n\npackage %s\n\n", f.tool, f.pkg.Name())
+
+	if len(f.imports) > 0 {
+		paths := make([]string, 0, len(f.imports))
+		for path := range f.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		b.WriteString("import (\n")
+		for _, path := range paths {
+			name := f.imports[path]
+			if i := strings.LastIndex(path, "/"); path[i+1:] == name {
+				fmt.Fprintf(&b, "\t%q\n", path)
+			} else {
+				fmt.Fprintf(&b, "\t%s %q\n", name, path)
+			}
+		}
+		b.WriteString(")\n\n")
+	}
+
+	b.Write(f.body.Bytes())
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %w", err)
+	}
+	return src, nil
+}
+
+// LookupStructs returns the struct types of pkg with the given names.
+func LookupStructs(pkg *types.Package, names []string) ([]*types.Named, error) {
+	var structs []*types.Named
+	var missing []string
+	for _, name := range names {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			missing = append(missing, name)
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			missing = append(missing, name)
+			continue
+		}
+		structs = append(structs, named)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("struct types not found in %s: %s", pkg.Path(), strings.Join(missing, ", "))
+	}
+	return structs, nil
+}
+
+// Field is an exported field of a struct type, possibly promoted from an embedded struct.
+type Field struct {
+	*types.Var
+	// Tag is the raw struct tag of the field.
+	Tag string
+}
+
+// Fields returns the exported fields of the struct type named. The fields of the embedded structs without a JSON name
+// are promoted, like encoding/json does, unless a field of the same name is declared at a shallower depth.
+func Fields(named *types.Named) []Field {
+	return structFields(named.Underlying().(*types.Struct), map[string]bool{})
+}
+
+func structFields(st *types.Struct, seen map[string]bool) []Field {
+	var fields []Field
+	var embedded []*types.Struct
+	for i := 0; i < st.NumFields(); i++ {
+		v, tag := st.Field(i), st.Tag(i)
+		if s, ok := v.Type().Underlying().(*types.Struct); ok && v.Embedded() && !hasJSONName(tag) {
+			embedded = append(embedded, s)
+			continue
+		}
+		if !v.Exported() || seen[v.Name()] {
+			continue
+		}
+		seen[v.Name()] = true
+		fields = append(fields, Field{Var: v, Tag: tag})
+	}
+	for _, s := range embedded {
+		fields = append(fields, structFields(s, seen)...)
+	}
+	return fields
+}
+
+func hasJSONName(tag string) bool {
+	name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+	return name != ""
+}
+
+// checkUndeclared returns an error if one of the names is already declared in the package of the file.
+func (f *File) checkUndeclared(names ...string) error {
+	for _, name := range names {
+		if f.pkg.Scope().Lookup(name) != nil {
+			return fmt.Errorf("%s is already declared in %s", name, f.pkg.Path())
+		}
+	}
+	return nil
+}
+
+// checkNoMember returns an error if one of the names is already a field or a method of named.
+func (f *File) checkNoMember(named *types.Named, names ...string) error {
+	for _, name := range names {
+		if obj, _, _ := types.LookupFieldOrMethod(named, true, f.pkg, name); obj != nil {
+			return fmt.Errorf("%s.%s is already declared", named.Obj().Name(), name)
+		}
+	}
+	return nil
+}
+
+// TagLiteral returns tag as a Go string literal, raw if possible.
+func TagLiteral(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// NotEqual returns the Go expression telling whether the values a and b of type t differ: a call to its Equal method
+// if it has one, such as time.Time, a comparison if t is comparable, or a call to reflect.DeepEqual otherwise.
+func (f *File) NotEqual(t types.Type, a, b string) string {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, f.pkg, "Equal")
+	if fn, ok := obj.(*types.Func); ok {
+		sig := fn.Type().(*types.Signature)
+		if sig.Params().Len() == 1 && sig.Results().Len() == 1 && types.Identical(sig.Params().At(0).Type(), t) &&
+			types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool]) {
+			return "!" + a + ".Equal(" + b + ")"
+		}
+	}
+	if _, isInterface := t.Underlying().(*types.Interface); types.Comparable(t) && !isInterface {
+		return a + " != " + b
+	}
+	return "!" + f.Qualify("reflect", "reflect", "DeepEqual") + "(" + a + ", " + b + ")"
+}
+
+// Generate writes the file output of the package in dir, with the code gen generates for each of the struct types
+// named typeNames. The previous content of output is ignored, so that an outdated file doesn't prevent regenerating it.
+func Generate(tool, dir, output string, typeNames []string, gen func(f *File, named *types.Named) error) error {
+	path := filepath.Join(dir, output)
+	pkgs, err := loader.LoadIgnoring(dir, []string{path}, ".")
+	if err != nil {
+		return err
+	}
+	structs, err := LookupStructs(pkgs[0].Types, typeNames)
+	if err != nil {
+		return err
+	}
+
+	f := NewFile(tool, pkgs[0].Types)
+	var errs []error
+	for _, named := range structs {
+		if err := gen(f, named); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	src, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, src, 0o644)
+}
+
+// Main is the main function of the generator commands of this module, whose usage is:
+//
+//	tool -type User,Post [-output file.go] [dir]
+//
+// The file is written to the package directory, dir defaulting to the current directory, and is named after the first
+// type and suffix by default, e.g. "user_patch.go".
+func Main(tool, suffix string, gen func(f *File, named *types.Named) error) {
+	if err := command(tool, suffix, os.Args[1:], gen); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", tool, err)
+		os.Exit(1)
+	}
+}
+
+func command(tool, suffix string, args []string, gen func(f *File, named *types.Named) error) error {
+	flags := flag.NewFlagSet(tool, flag.ExitOnError)
+	typeNames := flags.String("type", "", "comma-separated list of struct type names")
+	output := flags.String("output", "", "output file name, defaults to <type>"+suffix)
+	_ = flags.Parse(args)
+
+	if *typeNames == "" {
+		return fmt.Errorf("-type is required")
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("a single package directory is expected")
+	}
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	var names []string
+	for _, name := range strings.Split(*typeNames, ",") {
+		names = append(names, strings.TrimSpace(name))
+	}
+	if *output == "" {
+		*output = strings.ToLower(names[0]) + suffix
+	}
+	return Generate(tool, dir, *output, names, gen)
+}
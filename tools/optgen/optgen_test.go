@@ -0,0 +1,146 @@
+package optgen
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shimmerglass/go-optional/tools/internal/loader"
+)
+
+func loadModels(t *testing.T) *types.Package {
+	t.Helper()
+	pkgs, err := loader.Load("testdata/models", ".")
+	assert.NoError(t, err)
+	return pkgs[0].Types
+}
+
+// generate runs gen on the models with the given names, checks that the generated code compiles with the models,
+// and compares it to testdata/golden.
+func generate(t *testing.T, tool, golden string, gen func(f *File, named *types.Named) error, names ...string) {
+	t.Helper()
+	pkg := loadModels(t)
+	structs, err := LookupStructs(pkg, names)
+	assert.NoError(t, err)
+
+	f := NewFile(tool, pkg)
+	for _, named := range structs {
+		assert.NoError(t, gen(f, named))
+	}
+	src, err := f.Bytes()
+	assert.NoError(t, err)
+
+	dir := copyModels(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "gen.go"), src, 0o644))
+	_, err = loader.Load(dir, ".")
+	assert.NoError(t, err)
+
+	want, err := os.ReadFile(filepath.Join("testdata", golden))
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(src))
+}
+
+// copyModels copies the models package to a temporary directory and returns it.
+func copyModels(t *testing.T) string {
+	t.Helper()
+	// The packages must be in the module to be loaded, so the copy goes to testdata rather than to a temporary directory.
+	dir, err := os.MkdirTemp("testdata", "models")
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	files, err := filepath.Glob("testdata/models/*.go")
+	assert.NoError(t, err)
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, filepath.Base(name)), src, 0o644))
+	}
+	return dir
+}
+
+func TestFile_Import(t *testing.T) {
+	pkg := types.NewPackage("example.com/models", "models")
+	pkg.Scope().Insert(types.NewVar(0, pkg, "time", types.Typ[types.Int]))
+
+	f := NewFile("test", pkg)
+	assert.Equal(t, "", f.Import("example.com/models", "models"))
+	assert.Equal(t, "time2", f.Import("time", "time"))
+	assert.Equal(t, "json", f.Import("encoding/json", "json"))
+	assert.Equal(t, "json2", f.Import("example.com/json", "json"))
+	assert.Equal(t, "json", f.Import("encoding/json", "json"))
+	assert.Equal(t, "opt.Option", f.Opt("Option"))
+
+	f.Printf("var _ = %s\n", "json.Marshal")
+	src, err := f.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, `// Code generated by test; DO NOT EDIT.
+
+package models
+
+import (
+	"encoding/json"
+	json2 "example.com/json"
+	opt "github.com/shimmerglass/go-optional"
+	time2 "time"
+)
+
+var _ = json.Marshal
+`, string(src))
+}
+
+func TestLookupStructs(t *testing.T) {
+	pkg := loadModels(t)
+
+	structs, err := LookupStructs(pkg, []string{"User", "Box"})
+	assert.NoError(t, err)
+	assert.Len(t, structs, 2)
+
+	_, err = LookupStructs(pkg, []string{"User", "Missing"})
+	assert.EqualError(t, err, "struct types not found in github.com/shimmerglass/go-optional/tools/optgen/testdata/models: Missing")
+}
+
+func TestFields(t *testing.T) {
+	structs, err := LookupStructs(loadModels(t), []string{"User"})
+	assert.NoError(t, err)
+
+	var names []string
+	for _, f := range Fields(structs[0]) {
+		names = append(names, f.Name())
+	}
+	assert.Equal(t, []string{"ID", "Name", "Email", "Tags", "Settings", "CreatedAt"}, names)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := copyModels(t)
+	// An outdated generated file, which doesn't compile anymore.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "user_patch.go"), []byte("package models\n\nvar _ = User{}.Missing\n"), 0o644))
+
+	err := Generate("optpatch", dir, "user_patch.go", []string{"User"}, Patch)
+	assert.NoError(t, err)
+	pkgs, err := loader.Load(dir, ".")
+	assert.NoError(t, err)
+	assert.NotNil(t, pkgs[0].Types.Scope().Lookup("UserPatchFromDiff"))
+
+	err = Generate("optpatch", dir, "user_patch.go", []string{"Missing"}, Patch)
+	assert.ErrorContains(t, err, "struct types not found")
+}
+
+func TestCommand(t *testing.T) {
+	dir := copyModels(t)
+
+	assert.NoError(t, command("optaccessors", "_accessors.go", []string{"-type", "Post, Node", dir}, Accessors))
+	_, err := os.Stat(filepath.Join(dir, "post_accessors.go"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, command("optpatch", "_patch.go", []string{"-type", "User", "-output", "patches.go", dir}, Patch))
+	_, err = os.Stat(filepath.Join(dir, "patches.go"))
+	assert.NoError(t, err)
+
+	assert.EqualError(t, command("optpatch", "_patch.go", nil, Patch), "-type is required")
+	assert.EqualError(t, command("optpatch", "_patch.go", []string{"-type", "User", "a", "b"}, Patch), "a single package directory is expected")
+}
@@ -0,0 +1,122 @@
+package optgen
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+)
+
+// Patch generates the XxxPatch type of the struct type Xxx, whose fields are the exported fields of Xxx wrapped in
+// Option, with the same tags, so that a patch decodes with the same JSON, YAML or SQL codecs as an Xxx.
+// The patch gets Apply, IsEmpty and Merge methods, and an XxxPatchFromDiff constructor.
+// A field that is already an Option[T] in Xxx is an Option[Option[T]] in the patch, Some(None) clearing it. The patch
+// then gets an UnmarshalJSON method decoding a JSON null for such a field as Some(None), encoding/json leaving it None.
+func Patch(f *File, named *types.Named) error {
+	name := named.Obj().Name()
+	patch := name + "Patch"
+	fields := Fields(named)
+
+	// clearable holds the JSON names of the Option fields, by field name.
+	clearable := map[string]string{}
+	for _, field := range fields {
+		if _, ok := opttypes.OptionElem(field.Type()); !ok {
+			continue
+		}
+		tag, hasTag := reflect.StructTag(field.Tag).Lookup("json")
+		jsonName, _, _ := strings.Cut(tag, ",")
+		if hasTag && tag == "-" {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Name()
+		}
+		clearable[field.Name()] = jsonName
+	}
+
+	names := []string{patch, patch + "FromDiff"}
+	if len(clearable) > 0 {
+		names = append(names, "json"+patch)
+	}
+	if err := f.checkUndeclared(names...); err != nil {
+		return err
+	}
+	decl, args := f.TypeParams(named)
+
+	f.Printf("// %s is a partial update of %s: Apply sets the fields of a %s to the Some fields of the patch.\n", patch, name, name)
+	f.Printf("type %s%s struct {\n", patch, decl)
+	for _, field := range fields {
+		f.Printf("\t%s %s[%s]", field.Name(), f.Opt("Option"), f.Type(field.Type()))
+		if field.Tag != "" {
+			f.Printf(" %s", TagLiteral(field.Tag))
+		}
+		f.Printf("\n")
+	}
+	f.Printf("}\n\n")
+
+	f.Printf("// Apply sets the fields of v to the Some fields of p.\n")
+	f.Printf("func (p %s%s) Apply(v *%s%s) {\n", patch, args, name, args)
+	for _, field := range fields {
+		f.Printf("\tif p.%[1]s.IsSome() {\n\t\tv.%[1]s = p.%[1]s.Unwrap()\n\t}\n", field.Name())
+	}
+	f.Printf("}\n\n")
+
+	conds := []string{"true"}
+	if len(fields) > 0 {
+		conds = conds[:0]
+		for _, field := range fields {
+			conds = append(conds, "p."+field.Name()+".IsNone()")
+		}
+	}
+	f.Printf("// IsEmpty tells whether p changes nothing, all its fields being None.\n")
+	f.Printf("func (p %s%s) IsEmpty() bool {\n\treturn %s\n}\n\n", patch, args, strings.Join(conds, " &&\n\t\t"))
+
+	f.Printf("// Merge returns p updated with the Some fields of other, which take precedence.\n")
+	f.Printf("func (p %s%s) Merge(other %s%s) %s%s {\n", patch, args, patch, args, patch, args)
+	for _, field := range fields {
+		f.Printf("\tp.%[1]s = other.%[1]s.Or(p.%[1]s)\n", field.Name())
+	}
+	f.Printf("\treturn p\n}\n\n")
+
+	f.Printf("// %sFromDiff returns the patch turning from into to: its fields are Some where the fields of from and to differ.\n", patch)
+	f.Printf("func %sFromDiff%s(from, to %s%s) %s%s {\n", patch, decl, name, args, patch, args)
+	f.Printf("\tvar p %s%s\n", patch, args)
+	for _, field := range fields {
+		a, b := "from."+field.Name(), "to."+field.Name()
+		f.Printf("\tif %s {\n\t\tp.%s = %s(%s)\n\t}\n", f.NotEqual(field.Type(), a, b), field.Name(), f.Opt("Some"), b)
+	}
+	f.Printf("\treturn p\n}\n\n")
+
+	if len(clearable) > 0 {
+		patchJSON(f, patch, decl, args, fields, clearable)
+	}
+	return nil
+}
+
+// patchJSON generates the UnmarshalJSON method of the patch, decoding null as Some(None) for the clearable fields.
+func patchJSON(f *File, patch, decl, args string, fields []Field, clearable map[string]string) {
+	plain := "json" + patch
+	f.Printf("// %s is %s without its UnmarshalJSON method.\n", plain, patch)
+	f.Printf("type %s%s %s%s\n\n", plain, decl, patch, args)
+
+	f.Printf("// UnmarshalJSON decodes p like encoding/json does, except that null clears an Option field with Some(None).\n")
+	f.Printf("func (p *%s%s) UnmarshalJSON(data []byte) error {\n", patch, args)
+	f.Printf("\tif err := %s(data, (*%s%s)(p)); err != nil {\n\t\treturn err\n\t}\n", f.Qualify("encoding/json", "json", "Unmarshal"), plain, args)
+	f.Printf("\tvar fields map[string]%s\n", f.Qualify("encoding/json", "json", "RawMessage"))
+	f.Printf("\tif err := %s(data, &fields); err != nil {\n\t\treturn err\n\t}\n", f.Qualify("encoding/json", "json", "Unmarshal"))
+	f.Printf("\tfor key, value := range fields {\n")
+	f.Printf("\t\tif string(value) != \"null\" {\n\t\t\tcontinue\n\t\t}\n")
+	f.Printf("\t\tswitch {\n")
+	for _, field := range fields {
+		jsonName, ok := clearable[field.Name()]
+		if !ok {
+			continue
+		}
+		// Like encoding/json, the keys match the field names case-insensitively.
+		elem, _ := opttypes.OptionElem(field.Type())
+		f.Printf("\t\tcase %s(key, %q):\n", f.Qualify("strings", "strings", "EqualFold"), jsonName)
+		f.Printf("\t\t\tp.%s = %s(%s[%s]())\n", field.Name(), f.Opt("Some"), f.Opt("None"), f.Type(elem))
+	}
+	f.Printf("\t\t}\n\t}\n\treturn nil\n}\n\n")
+}
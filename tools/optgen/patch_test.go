@@ -0,0 +1,64 @@
+package optgen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatch(t *testing.T) {
+	generate(t, "optpatch", "patch.golden", Patch, "User", "Box")
+}
+
+func TestPatch_Errors(t *testing.T) {
+	pkg := loadModels(t)
+	structs, err := LookupStructs(pkg, []string{"Post"})
+	assert.NoError(t, err)
+
+	err = Patch(NewFile("optpatch", pkg), structs[0])
+	assert.EqualError(t, err, "PostPatch is already declared in github.com/shimmerglass/go-optional/tools/optgen/testdata/models")
+}
+
+func TestPatch_JSONNull(t *testing.T) {
+	pkg := loadModels(t)
+	structs, err := LookupStructs(pkg, []string{"User"})
+	assert.NoError(t, err)
+	f := NewFile("optpatch", pkg)
+	assert.NoError(t, Patch(f, structs[0]))
+	src, err := f.Bytes()
+	assert.NoError(t, err)
+
+	dir := copyModels(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "gen.go"), src, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "gen_test.go"), []byte(`package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	opt "github.com/shimmerglass/go-optional"
+)
+
+func TestUserPatch_UnmarshalJSON(t *testing.T) {
+	var p UserPatch
+	if err := json.Unmarshal([]byte(`+"`"+`{"EMAIL": null, "name": "bob", "tags": null}`+"`"+`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Email != opt.Some(opt.None[string]()) || p.Name != opt.Some("bob") || p.Tags.IsSome() || p.ID.IsSome() {
+		t.Fatalf("%+v", p)
+	}
+
+	u := User{Email: opt.Some("bob@example.com")}
+	p.Apply(&u)
+	if u.Email.IsSome() {
+		t.Fatalf("%+v", u)
+	}
+}
+`), 0o644))
+
+	out, err := exec.Command("go", "test", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	maybe "github.com/shimmerglass/go-optional"
+)
+
+// Option is unrelated to the opt package.
+type Option[T any] struct {
+	Value T
+}
+
+type Order struct {
+	ID       int64
+	Customer string
+	Note     maybe.Option[string]
+	Label    Option[string]
+	Discount maybe.Option[float64]
+	total    float64
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value maybe.Option[V]
+}
+
+type Account struct {
+	Name string
+	name string
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	opt "github.com/shimmerglass/go-optional"
+)
+
+type Timestamps struct {
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type User struct {
+	ID       int64              `json:"id" db:"id,pk"`
+	Name     string             `json:"name" yaml:"name"`
+	Email    opt.Option[string] `json:"email"`
+	Tags     []string           `json:"tags"`
+	Settings map[string]any     `json:"settings"`
+	Timestamps
+	password string
+}
+
+type Box[T any] struct {
+	Value T            `json:"value"`
+	Items map[string]T `json:"items"`
+}
+
+type Post struct {
+	Title  *string `json:"title"`
+	Rating *float64
+	Author *User
+	Draft  bool
+	Timestamps
+}
+
+// PostPatch is declared by hand.
+type PostPatch struct{}
+
+type Counter struct {
+	Count *int
+}
+
+func (c *Counter) SetCount(n int) {
+	c.Count = &n
+}
+
+type Node[T any] struct {
+	Value *T
+	Next  *Node[T]
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	opt "github.com/shimmerglass/go-optional"
+)
+
+type Event struct {
+	ID        int64                      `json:"id,string"`
+	Kind      string                     `json:"kind"`
+	At        time.Time                  `json:"at"`
+	Until     opt.Option[time.Time]      `json:"until"`
+	Note      opt.Option[string]         `json:"note,omitzero"`
+	Parent    opt.Option[*Event]         `json:"parent,omitzero"`
+	Score     *float64                   `json:"score,omitempty"`
+	Retries   *int                       `json:"retries,string"`
+	Labels    []string                   `json:"labels,omitempty"`
+	Counts    map[string]int             `json:"counts"`
+	Payload   json.RawMessage            `json:"payload"`
+	Data      []byte                     `json:"data"`
+	Total     json.Number                `json:"total"`
+	Slots     [2]opt.Option[int]         `json:"slots"`
+	Fixed     [3]int                     `json:"fixed,omitempty"`
+	Empty     [0]int                     `json:"empty,omitempty"`
+	Attendees []Box[opt.Option[User]]    `json:"attendees"`
+	Location  struct{ Lat, Lng float64 } `json:"location"`
+	Hidden    opt.Option[Counter]        `json:"hidden,omitempty"`
+	Secret    string                     `json:"-"`
+	Dash      string                     `json:"-,"`
+	Type      string                     `json:"content-type"`
+	Extra     any                        `json:"extra,omitempty"`
+	Meta      `json:"meta"`
+	*Audit
+	internal string
+}
+
+type Meta struct {
+	Source string `json:"source"`
+}
+
+type Audit struct {
+	By string    `json:"by"`
+	At time.Time `json:"at"`
+}
@@ -0,0 +1,363 @@
+package optgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/types"
+	"go/version"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shimmerglass/go-optional/internal/jsonfields"
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+)
+
+// TypeScript returns the TypeScript interfaces describing the JSON encoding of the struct types roots, and of the named
+// struct types they refer to, as encoding/json and Option.MarshalJSON produce it:
+//
+//   - Option[T] fields are "field: T | null", or "field?: T" with the omitzero option, None being omitted then.
+//     encoding/json only honors omitzero from Go 1.24, so it's ignored if goVersion, the Go version of the module
+//     encoding the JSON, e.g. "1.23" or "go1.23", is older or empty.
+//   - Pointers, slices, maps and interfaces can be null. Fields with the omitempty option whose type can be empty are optional.
+//   - Fields are named after their json tags, and the fields of embedded structs without a JSON name are promoted.
+//     The string option makes numbers and booleans strings.
+//   - time.Time is a string, as are the types implementing encoding.TextMarshaler. The other types implementing
+//     json.Marshaler are unknown.
+//   - Generic struct types are generic interfaces.
+//
+// The interfaces are named after the Go types, which must have distinct names.
+func TypeScript(roots []*types.Named, goVersion string) ([]byte, error) {
+	if !strings.HasPrefix(goVersion, "go") {
+		goVersion = "go" + goVersion
+	}
+	g := &tsGenerator{
+		names:    map[string]*types.Named{},
+		declared: map[*types.Named]bool{},
+		omitzero: version.Compare(goVersion, "go1.24") >= 0,
+	}
+	for _, named := range roots {
+		g.ref(named.Origin())
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by optts; DO NOT EDIT.\n")
+	for len(g.queue) > 0 {
+		named := g.queue[0]
+		g.queue = g.queue[1:]
+		g.declare(&b, named)
+	}
+	if len(g.errs) > 0 {
+		return nil, errors.Join(g.errs...)
+	}
+	return b.Bytes(), nil
+}
+
+type tsGenerator struct {
+	queue    []*types.Named
+	names    map[string]*types.Named
+	declared map[*types.Named]bool
+	// omitzero tells whether encoding/json honors the omitzero option.
+	omitzero bool
+	errs     []error
+}
+
+func (g *tsGenerator) errorf(format string, args ...any) {
+	g.errs = append(g.errs, fmt.Errorf(format, args...))
+}
+
+// ref returns the name of the interface of named, with its type arguments, and queues its declaration.
+func (g *tsGenerator) ref(named *types.Named) string {
+	origin := named.Origin()
+	name := origin.Obj().Name()
+	if other, ok := g.names[name]; !ok {
+		g.names[name] = origin
+	} else if other != origin && !g.declared[origin] {
+		g.errorf("%s and %s are both named %s", other.Obj().Pkg().Path()+"."+name, origin.Obj().Pkg().Path()+"."+name, name)
+	}
+	if !g.declared[origin] {
+		g.declared[origin] = true
+		g.queue = append(g.queue, origin)
+	}
+
+	targs := named.TypeArgs()
+	if targs.Len() == 0 {
+		if tparams := named.TypeParams(); tparams.Len() > 0 {
+			// The generic type itself, e.g. a root.
+			names := make([]string, tparams.Len())
+			for i := range names {
+				names[i] = tparams.At(i).Obj().Name()
+			}
+			return name + "<" + strings.Join(names, ", ") + ">"
+		}
+		return name
+	}
+	args := make([]string, targs.Len())
+	for i := range args {
+		args[i] = g.typeString(targs.At(i), name, "  ")
+	}
+	return name + "<" + strings.Join(args, ", ") + ">"
+}
+
+func (g *tsGenerator) declare(b *bytes.Buffer, named *types.Named) {
+	name := named.Obj().Name()
+	if tparams := named.TypeParams(); tparams.Len() > 0 {
+		names := make([]string, tparams.Len())
+		for i := range names {
+			names[i] = tparams.At(i).Obj().Name()
+		}
+		name += "<" + strings.Join(names, ", ") + ">"
+	}
+
+	fmt.Fprintf(b, "\nexport interface %s ", name)
+	b.WriteString(g.object(named.Underlying().(*types.Struct), "  ", named.Obj().Name()))
+	b.WriteString("\n")
+}
+
+// object returns the TypeScript object type of a struct, with its fields indented with indent.
+func (g *tsGenerator) object(st *types.Struct, indent, context string) string {
+	fields := jsonFields(st)
+	if len(fields) == 0 {
+		return "{}"
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, field := range fields {
+		where := context + "." + field.GoName
+		t, null := g.typeExpr(field.Type, where, indent), nullable(field.Type)
+		optional := field.ViaPointer
+		switch {
+		case field.OmitZero && g.omitzero:
+			// A nil pointer or None is omitted as the zero value, rather than null.
+			optional = true
+			if _, ok := opttypes.OptionElem(field.Type); ok || isNilable(field.Type) {
+				null = elemNull(field.Type)
+			}
+		case field.OmitEmpty && canBeEmpty(field.Type):
+			optional = true
+			if isNilable(field.Type) {
+				null = elemNull(field.Type)
+			}
+		}
+		if field.Quoted {
+			t = "string"
+		}
+
+		b.WriteString(indent)
+		b.WriteString(tsPropertyName(field.Name))
+		if optional {
+			b.WriteString("?")
+		}
+		b.WriteString(": ")
+		b.WriteString(t)
+		if null {
+			b.WriteString(" | null")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString(indent[:len(indent)-2])
+	b.WriteString("}")
+	return b.String()
+}
+
+// typeString returns the TypeScript type of the JSON encoding of t, including null. where tells where t is used, for
+// errors, and indent is the indentation of the anonymous structs.
+func (g *tsGenerator) typeString(t types.Type, where, indent string) string {
+	s := g.typeExpr(t, where, indent)
+	if nullable(t) {
+		return s + " | null"
+	}
+	return s
+}
+
+// typeExpr returns the TypeScript type of the JSON encoding of t, without null.
+func (g *tsGenerator) typeExpr(t types.Type, where, indent string) string {
+	t = types.Unalias(t)
+	if elem, ok := opttypes.OptionElem(t); ok {
+		return g.typeExpr(elem, where, indent)
+	}
+	if tp, ok := t.(*types.TypeParam); ok {
+		return tp.Obj().Name()
+	}
+	switch {
+	case isNamed(t, "time", "Time"):
+		return "string"
+	case isNamed(t, "encoding/json", "Number"):
+		return "number"
+	case hasMethod(t, "MarshalJSON"):
+		return "unknown"
+	case hasMethod(t, "MarshalText"):
+		return "string"
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "boolean"
+		case u.Info()&(types.IsInteger|types.IsFloat) != 0:
+			return "number"
+		case u.Info()&types.IsString != 0:
+			return "string"
+		}
+	case *types.Pointer:
+		return g.typeExpr(u.Elem(), where, indent)
+	case *types.Slice:
+		if isBytes(u) {
+			// Base64-encoded.
+			return "string"
+		}
+		return g.array(u.Elem(), where, indent)
+	case *types.Array:
+		return g.array(u.Elem(), where, indent)
+	case *types.Map:
+		if !isMapKey(u.Key()) {
+			break
+		}
+		return "Record<string, " + g.typeString(u.Elem(), where, indent) + ">"
+	case *types.Interface:
+		return "unknown"
+	case *types.Struct:
+		if named, ok := t.(*types.Named); ok {
+			return g.ref(named)
+		}
+		return g.object(u, indent+"  ", where)
+	}
+	g.errorf("%s: %s cannot be encoded to JSON", where, t)
+	return "unknown"
+}
+
+// nullable tells whether the JSON encoding of t can be null, besides unknown values.
+func nullable(t types.Type) bool {
+	t = types.Unalias(t)
+	if _, ok := opttypes.OptionElem(t); ok {
+		return true
+	}
+	if _, ok := t.(*types.TypeParam); ok || isNamed(t, "encoding/json", "Number") || hasMethod(t, "MarshalJSON") {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map:
+		return true
+	}
+	return false
+}
+
+// elemNull tells whether the values of t other than nil or None can be null. The values of slices and maps never are.
+func elemNull(t types.Type) bool {
+	if elem, ok := opttypes.OptionElem(t); ok {
+		return nullable(elem)
+	}
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		return nullable(p.Elem())
+	}
+	return false
+}
+
+func isNamed(t types.Type, path, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == path && named.Obj().Name() == name
+}
+
+func isBytes(s *types.Slice) bool {
+	b, ok := s.Elem().Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Byte && !hasMethod(s.Elem(), "MarshalJSON") && !hasMethod(s.Elem(), "MarshalText")
+}
+
+// isMapKey tells whether encoding/json accepts t as a map key: strings, integers and encoding.TextMarshaler.
+func isMapKey(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return hasMethod(t, "MarshalText") || ok && b.Info()&(types.IsString|types.IsInteger) != 0
+}
+
+// hasMethod tells whether t or *t has the method name with no parameters returning a value and an error, as
+// json.Marshaler and encoding.TextMarshaler do.
+func hasMethod(t types.Type, name string) bool {
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, name)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 0 && sig.Results().Len() == 2
+}
+
+func isNilable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// canBeEmpty tells whether the values of t can be empty for the omitempty option.
+func canBeEmpty(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Basic, *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+		return true
+	case *types.Array:
+		return u.Len() == 0
+	}
+	return false
+}
+
+func (g *tsGenerator) array(elem types.Type, where, indent string) string {
+	if nullable(elem) {
+		return "(" + g.typeExpr(elem, where, indent) + " | null)[]"
+	}
+	return g.typeExpr(elem, where, indent) + "[]"
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// jsonFields returns the fields of the JSON encoding of st.
+func jsonFields(st *types.Struct) []jsonfields.Field[types.Type] {
+	return jsonfields.Of[types.Type](goTypes{}, st)
+}
+
+// goTypes adapts go/types to the rules of encoding/json.
+type goTypes struct{}
+
+func (goTypes) Fields(t types.Type) []jsonfields.Decl[types.Type] {
+	st := t.Underlying().(*types.Struct)
+	fields := make([]jsonfields.Decl[types.Type], st.NumFields())
+	for i := range fields {
+		v := st.Field(i)
+		fields[i] = jsonfields.Decl[types.Type]{Name: v.Name(), Type: v.Type(), Tag: reflect.StructTag(st.Tag(i)), Exported: v.Exported(), Embedded: v.Embedded()}
+	}
+	return fields
+}
+
+func (goTypes) Pointee(t types.Type) (types.Type, bool) {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		return p.Elem(), true
+	}
+	return nil, false
+}
+
+func (goTypes) IsStruct(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+func (goTypes) Marshals(t types.Type) bool {
+	return hasMethod(t, "MarshalJSON") || hasMethod(t, "MarshalText")
+}
+
+func (goTypes) IsScalar(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0
+}
@@ -0,0 +1,53 @@
+package optgen
+
+import (
+	"go/types"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeScript(t *testing.T) {
+	pkg := loadModels(t)
+	structs, err := LookupStructs(pkg, []string{"Event", "Post", "Node"})
+	assert.NoError(t, err)
+
+	src, err := TypeScript(structs, "1.24")
+	assert.NoError(t, err)
+
+	want, err := os.ReadFile("testdata/typescript.golden")
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(src))
+}
+
+func TestTypeScript_OmitzeroBeforeGo124(t *testing.T) {
+	pkg := loadModels(t)
+	structs, err := LookupStructs(pkg, []string{"Event"})
+	assert.NoError(t, err)
+
+	src, err := TypeScript(structs, "go1.23.4")
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "\n  note: string | null;\n  parent: Event | null;\n")
+}
+
+func TestTypeScript_Errors(t *testing.T) {
+	a := types.NewPackage("example.com/a", "a")
+	b := types.NewPackage("example.com/b", "b")
+	newStruct := func(pkg *types.Package, name string, fields ...*types.Var) *types.Named {
+		obj := types.NewTypeName(0, pkg, name, nil)
+		return types.NewNamed(obj, types.NewStruct(fields, nil), nil)
+	}
+
+	item := newStruct(b, "Item")
+	order := newStruct(a, "Order",
+		types.NewField(0, a, "Done", types.NewChan(types.SendRecv, types.Typ[types.Bool]), false),
+		types.NewField(0, a, "Item", item, false),
+		types.NewField(0, a, "Items", types.NewSlice(item), false),
+		types.NewField(0, a, "Lines", types.NewMap(types.Typ[types.Float64], types.Typ[types.Int]), false),
+	)
+	_, err := TypeScript([]*types.Named{order, newStruct(a, "Item")}, "1.24")
+	assert.EqualError(t, err, "Order.Done: chan bool cannot be encoded to JSON\n"+
+		"example.com/a.Item and example.com/b.Item are both named Item\n"+
+		"Order.Lines: map[float64]int cannot be encoded to JSON")
+}
@@ -0,0 +1,575 @@
+// Package optmigrate rewrites struct fields of pointer types *T to opt.Option[T], along with the code using them.
+package optmigrate
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+)
+
+// Issue is a use of a migrated field that cannot be rewritten safely, and is left unchanged for a manual migration.
+type Issue struct {
+	Pos     token.Position
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Pos, i.Message)
+}
+
+// Result is the outcome of Migrate.
+type Result struct {
+	// Files holds the new content of the rewritten files, by file name.
+	Files map[string][]byte
+	// Issues lists the uses left unchanged, sorted by position.
+	Issues []Issue
+}
+
+// Migrate changes the types of the selected fields from *T to opt.Option[T], and rewrites their uses in pkgs:
+//
+//   - x.F != nil and x.F == nil become x.F.IsSome() and x.F.IsNone().
+//   - *x.F and x.F.Sub, when only read, become x.F.Unwrap() and x.F.Unwrap().Sub.
+//     Note that Unwrap gives the zero value for None, where dereferencing a nil pointer panics.
+//   - x.F = &v and x.F = nil become x.F = opt.Some(v) and x.F = opt.None[T](), and any other pointer p assigned
+//     to x.F becomes opt.FromNillable(p). The same goes for the values of composite literals.
+//
+// Fields are selected as "Type.Field", or "Type" for all the pointer fields of a struct type, among the types declared in pkgs.
+// Any other use, such as passing x.F to a function or writing through it, is reported as an Issue.
+// Load pkgs with their tests, e.g. with loader.LoadWithTests, so that the test files are migrated too. Nothing is written to disk.
+func Migrate(pkgs []*packages.Package, fields []string) (*Result, error) {
+	res := &Result{Files: map[string][]byte{}}
+	if len(pkgs) == 0 {
+		return res, nil
+	}
+
+	m := &migration{fset: pkgs[0].Fset, edits: map[string][]edit{}}
+	var err error
+	m.selected, err = selectFields(pkgs, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// The test variant of a package holds the same files as the package.
+	seen := map[string]bool{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			name := m.fset.File(file.Pos()).Name()
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			m.migrateFile(pkg, file)
+		}
+	}
+	if len(m.errs) > 0 {
+		return nil, errors.Join(m.errs...)
+	}
+
+	for name, edits := range m.edits {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		out, err := applyEdits(src, edits)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if res.Files[name], err = format.Source(out); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	res.Issues = m.issues
+	sort.SliceStable(res.Issues, func(i, j int) bool {
+		a, b := res.Issues[i].Pos, res.Issues[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return res, nil
+}
+
+// selectFields returns the declaration positions of the selected fields.
+// Fields are identified by position, as the test variant of a package has its own copy of the package types.
+func selectFields(pkgs []*packages.Package, fields []string) (map[token.Position]bool, error) {
+	selected := map[token.Position]bool{}
+	var errs []error
+	for _, sel := range fields {
+		typeName, fieldName, _ := strings.Cut(sel, ".")
+		found := false
+		for _, pkg := range pkgs {
+			obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			st, ok := obj.Type().Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for i := 0; i < st.NumFields(); i++ {
+				f := st.Field(i)
+				if f.Embedded() || (fieldName != "" && f.Name() != fieldName) {
+					continue
+				}
+				if _, ok := pointerElem(f.Type()); ok {
+					selected[pkg.Fset.Position(f.Pos())] = true
+					found = true
+				}
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("no pointer field matches %s", sel))
+		}
+	}
+	return selected, errors.Join(errs...)
+}
+
+func pointerElem(t types.Type) (types.Type, bool) {
+	p, ok := types.Unalias(t).(*types.Pointer)
+	if !ok {
+		return nil, false
+	}
+	return p.Elem(), true
+}
+
+// edit replaces the bytes from start to end of a file with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+func applyEdits(src []byte, edits []edit) ([]byte, error) {
+	// Insertions go before the replacements starting at the same offset, and keep their order.
+	sort.SliceStable(edits, func(i, j int) bool {
+		a, b := edits[i], edits[j]
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		return a.start == a.end && b.start != b.end
+	})
+
+	var out []byte
+	last := 0
+	for _, e := range edits {
+		if e.start < last {
+			return nil, fmt.Errorf("overlapping edits at offset %d", e.start)
+		}
+		out = append(out, src[last:e.start]...)
+		out = append(out, e.text...)
+		last = e.end
+	}
+	return append(out, src[last:]...), nil
+}
+
+type migration struct {
+	fset     *token.FileSet
+	selected map[token.Position]bool
+	edits    map[string][]edit
+	issues   []Issue
+	errs     []error
+}
+
+func (m *migration) isSelected(v *types.Var) bool {
+	return v != nil && v.IsField() && m.selected[m.fset.Position(v.Origin().Pos())]
+}
+
+func (m *migration) replace(pos, end token.Pos, text string) {
+	p := m.fset.Position(pos)
+	m.edits[p.Filename] = append(m.edits[p.Filename], edit{start: p.Offset, end: m.fset.Position(end).Offset, text: text})
+}
+
+func (m *migration) insert(pos token.Pos, text string) {
+	m.replace(pos, pos, text)
+}
+
+func (m *migration) report(n ast.Node, format string, args ...any) {
+	m.issues = append(m.issues, Issue{Pos: m.fset.Position(n.Pos()), Message: fmt.Sprintf(format, args...)})
+}
+
+// fileMigration migrates a file of a package.
+type fileMigration struct {
+	*migration
+	pkg     *packages.Package
+	file    *ast.File
+	imports map[string]string
+	// optName is the name the opt package is imported under, "" in the opt package itself.
+	optName string
+	// hasImport tells whether the opt package can be used in the file as is, and needImport whether it must be imported.
+	hasImport, needImport bool
+	// done holds the field references already handled as part of their parent, e.g. as the value of another migrated field.
+	done map[ast.Node]bool
+}
+
+func (m *migration) migrateFile(pkg *packages.Package, file *ast.File) {
+	f := &fileMigration{migration: m, pkg: pkg, file: file, imports: fileImports(pkg, file), done: map[ast.Node]bool{}}
+	switch name, ok := f.imports[opttypes.PkgPath]; {
+	case pkg.PkgPath == opttypes.PkgPath:
+		f.hasImport = true
+	case ok && name != "_":
+		f.optName, f.hasImport = name, true
+	default:
+		f.optName = importName(pkg, file, "opt")
+	}
+
+	var stack []ast.Node
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.Field:
+			f.field(n)
+		case *ast.CompositeLit:
+			f.compositeLit(n)
+		case *ast.SelectorExpr:
+			if v := f.fieldRef(n); v != nil && !f.done[n] {
+				f.use(n, v, stack)
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+
+	if f.needImport {
+		f.addImport()
+	}
+}
+
+// fileImports returns the names the packages imported by file are known as, by import path.
+func fileImports(pkg *packages.Package, file *ast.File) map[string]string {
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			imports[path] = spec.Name.Name
+		} else if imp, ok := pkg.Imports[path]; ok {
+			imports[path] = imp.Name
+		}
+	}
+	return imports
+}
+
+// importName returns name, or name2, name3… if it's already used in file or in the scope of pkg.
+func importName(pkg *packages.Package, file *ast.File, name string) string {
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	n := name
+	for i := 2; used[n] || pkg.Types.Scope().Lookup(n) != nil; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	return n
+}
+
+// qualify returns the name of a member of the opt package as written in the file.
+func (f *fileMigration) qualify(name string) string {
+	if !f.hasImport {
+		f.needImport = true
+	}
+	switch f.optName {
+	case "", ".":
+		return name
+	}
+	return f.optName + "." + name
+}
+
+func (f *fileMigration) addImport() {
+	spec := f.optName + ` "` + opttypes.PkgPath + `"`
+	for _, decl := range f.file.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			break
+		}
+		if d.Lparen.IsValid() {
+			f.insert(d.Lparen+1, "\n"+spec)
+		} else {
+			f.insert(d.Specs[0].Pos(), "(\n"+spec+"\n")
+			f.insert(d.End(), "\n)")
+		}
+		return
+	}
+	f.insert(f.file.Name.End(), "\n\nimport "+spec)
+}
+
+// typeString returns t as written in the file, or false if it refers to a package the file doesn't import.
+func (f *fileMigration) typeString(t types.Type) (string, bool) {
+	ok := true
+	s := types.TypeString(t, func(pkg *types.Package) string {
+		if pkg.Path() == f.pkg.PkgPath {
+			return ""
+		}
+		name, found := f.imports[pkg.Path()]
+		switch {
+		case !found || name == "_":
+			ok = false
+		case name == ".":
+			return ""
+		}
+		return name
+	})
+	return s, ok
+}
+
+// fieldRef returns the migrated field sel refers to, if any.
+func (f *fileMigration) fieldRef(sel *ast.SelectorExpr) *types.Var {
+	s, ok := f.pkg.TypesInfo.Selections[sel]
+	if !ok || s.Kind() != types.FieldVal {
+		return nil
+	}
+	v, _ := s.Obj().(*types.Var)
+	if !f.isSelected(v) {
+		return nil
+	}
+	return v
+}
+
+func (f *fileMigration) isNil(e ast.Expr) bool {
+	tv, ok := f.pkg.TypesInfo.Types[e]
+	return ok && tv.IsNil()
+}
+
+// field rewrites the type of a migrated field declaration.
+func (f *fileMigration) field(field *ast.Field) {
+	n := 0
+	for _, name := range field.Names {
+		if v, ok := f.pkg.TypesInfo.Defs[name].(*types.Var); ok && f.isSelected(v) {
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+	if n < len(field.Names) {
+		f.errs = append(f.errs, fmt.Errorf("%s: fields declared together must be migrated together", f.fset.Position(field.Pos())))
+		return
+	}
+
+	star, ok := field.Type.(*ast.StarExpr)
+	if !ok {
+		f.report(field.Type, "the type of %s is not written as *T, rewrite it by hand", field.Names[0].Name)
+		return
+	}
+	f.replace(star.Star, star.Star+1, f.qualify("Option["))
+	f.insert(star.End(), "]")
+
+	if field.Tag == nil {
+		return
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+	_, opts, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+	for _, o := range strings.Split(opts, ",") {
+		if o == "omitempty" {
+			f.report(field.Tag, "omitempty has no effect on Option fields: None is marshaled as null")
+		}
+	}
+}
+
+// compositeLit rewrites the values of the migrated fields in a struct literal.
+func (f *fileMigration) compositeLit(lit *ast.CompositeLit) {
+	t := f.pkg.TypesInfo.TypeOf(lit)
+	if elem, ok := pointerElem(t); ok {
+		t = elem
+	}
+	if t == nil {
+		return
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	for i, elt := range lit.Elts {
+		var field *types.Var
+		value := elt
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if id, ok := kv.Key.(*ast.Ident); ok {
+				field, _ = f.pkg.TypesInfo.Uses[id].(*types.Var)
+			}
+			value = kv.Value
+		} else if i < st.NumFields() {
+			field = st.Field(i)
+		}
+		if f.isSelected(field) {
+			elem, _ := pointerElem(field.Type())
+			f.value(value, elem)
+		}
+	}
+}
+
+// value rewrites e, a *T value assigned to a migrated field, to an opt.Option[T].
+func (f *fileMigration) value(e ast.Expr, elem types.Type) {
+	inner := ast.Unparen(e)
+	if f.isNil(inner) {
+		t, ok := f.typeString(elem)
+		if !ok {
+			f.report(e, "cannot write None[%s] in this file, rewrite it by hand", elem)
+			return
+		}
+		f.replace(e.Pos(), e.End(), f.qualify("None["+t+"]()"))
+		return
+	}
+	if u, ok := inner.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		f.replace(u.OpPos, u.OpPos+1, f.qualify("Some("))
+		f.insert(u.End(), ")")
+		return
+	}
+	if sel, ok := inner.(*ast.SelectorExpr); ok && f.fieldRef(sel) != nil {
+		// Already an Option once migrated.
+		f.done[sel] = true
+		return
+	}
+	f.insert(e.Pos(), f.qualify("FromNillable("))
+	f.insert(e.End(), ")")
+}
+
+// use rewrites ref, a reference to the migrated field v, whose ancestors are in stack.
+func (f *fileMigration) use(ref *ast.SelectorExpr, v *types.Var, stack []ast.Node) {
+	var cur ast.Expr = ref
+	i := len(stack) - 1
+	for ; i >= 0; i-- {
+		p, ok := stack[i].(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		cur = p
+	}
+	var parent ast.Node
+	if i >= 0 {
+		parent = stack[i]
+	}
+	elem, _ := pointerElem(v.Type())
+	name := types.ExprString(ref)
+
+	switch p := parent.(type) {
+	case *ast.BinaryExpr:
+		if p.Op != token.EQL && p.Op != token.NEQ {
+			break
+		}
+		other := p.X
+		if other == cur {
+			other = p.Y
+		}
+		if !f.isNil(other) {
+			f.report(p, "%s is compared to a pointer, rewrite it by hand", name)
+			return
+		}
+		method := ".IsNone()"
+		if p.Op == token.NEQ {
+			method = ".IsSome()"
+		}
+		if p.X == cur {
+			f.replace(cur.End(), p.End(), method)
+		} else {
+			f.replace(p.Pos(), cur.Pos(), "")
+			f.insert(cur.End(), method)
+		}
+		return
+	case *ast.StarExpr:
+		if !f.readOnly(p, elem, stack[:i]) {
+			f.report(p, "the value of %s is written, addressed or has a pointer method called, rewrite it by hand", name)
+			return
+		}
+		f.replace(p.Star, p.Star+1, "")
+		f.insert(cur.End(), ".Unwrap()")
+		return
+	case *ast.SelectorExpr:
+		// x.F.Sub or x.F.Method, through an implicit dereference.
+		if !f.readOnly(cur, elem, stack[:i+1]) {
+			f.report(p, "the value of %s is written, addressed or has a pointer method called, rewrite it by hand", name)
+			return
+		}
+		f.insert(cur.End(), ".Unwrap()")
+		return
+	case *ast.AssignStmt:
+		idx := -1
+		for j, lhs := range p.Lhs {
+			if lhs == cur {
+				idx = j
+			}
+		}
+		if idx < 0 || p.Tok != token.ASSIGN {
+			break
+		}
+		if len(p.Rhs) != len(p.Lhs) {
+			f.report(p, "%s is assigned a value of a multi-value expression, rewrite it by hand", name)
+			return
+		}
+		f.value(p.Rhs[idx], elem)
+		return
+	}
+	f.report(ref, "%s is used as a pointer, rewrite it by hand, e.g. with UnwrapAsPtr", name)
+}
+
+// readOnly tells whether the variable e, of type t, is only read by its ancestors, so that it can be replaced by the copy
+// Unwrap returns. e is the dereference of a migrated field, explicit or implicit.
+func (f *fileMigration) readOnly(e ast.Expr, t types.Type, ancestors []ast.Node) bool {
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		_, isPtr := t.Underlying().(*types.Pointer)
+		switch p := ancestors[i].(type) {
+		case *ast.ParenExpr:
+		case *ast.StarExpr:
+			// What is written through a pointer is shared with the copy.
+			return true
+		case *ast.SelectorExpr:
+			s, ok := f.pkg.TypesInfo.Selections[p]
+			if !ok || isPtr {
+				return true
+			}
+			if s.Kind() != types.FieldVal {
+				// A method with a pointer receiver needs an addressable operand, which a copy isn't.
+				_, ptrRecv := s.Obj().Type().(*types.Signature).Recv().Type().(*types.Pointer)
+				return !ptrRecv
+			}
+		case *ast.IndexExpr:
+			if _, ok := t.Underlying().(*types.Array); !ok || p.X != e {
+				return true
+			}
+		case *ast.SliceExpr:
+			_, ok := t.Underlying().(*types.Array)
+			return !ok
+		case *ast.AssignStmt:
+			for _, lhs := range p.Lhs {
+				if lhs == e {
+					return false
+				}
+			}
+			return true
+		case *ast.RangeStmt:
+			return p.Tok != token.ASSIGN || (p.Key != e && p.Value != e)
+		case *ast.IncDecStmt:
+			return false
+		case *ast.UnaryExpr:
+			return p.Op != token.AND
+		default:
+			return true
+		}
+
+		e = ancestors[i].(ast.Expr)
+		t = f.pkg.TypesInfo.TypeOf(e)
+	}
+	return true
+}
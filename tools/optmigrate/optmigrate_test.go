@@ -0,0 +1,70 @@
+package optmigrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shimmerglass/go-optional/tools/internal/loader"
+)
+
+func TestMigrate(t *testing.T) {
+	pkgs, err := loader.LoadWithTests("testdata/p", ".")
+	assert.NoError(t, err)
+
+	res, err := Migrate(pkgs, []string{"User", "Address.Zip"})
+	assert.NoError(t, err)
+
+	var files []string
+	for name, src := range res.Files {
+		base := filepath.Base(name)
+		files = append(files, base)
+
+		golden, err := os.ReadFile(filepath.Join("testdata/p", base+".golden"))
+		assert.NoError(t, err)
+		assert.Equal(t, string(golden), string(src), base)
+	}
+	sort.Strings(files)
+	assert.Equal(t, []string{"alias.go", "p.go", "p_test.go", "shadow.go", "use.go"}, files)
+
+	var issues []string
+	for _, issue := range res.Issues {
+		issue.Pos.Filename = filepath.Base(issue.Pos.Filename)
+		issues = append(issues, issue.String())
+	}
+	assert.Equal(t, []string{
+		"p.go:20:19: omitempty has no effect on Option fields: None is marshaled as null",
+		"p_test.go:7:36: cannot write None[time.Time] in this file, rewrite it by hand",
+		"use.go:25:2: the value of u.Address is written, addressed or has a pointer method called, rewrite it by hand",
+		"use.go:26:2: the value of u.Address is written, addressed or has a pointer method called, rewrite it by hand",
+		"use.go:29:10: u.Born is used as a pointer, rewrite it by hand, e.g. with UnwrapAsPtr",
+	}, issues)
+}
+
+func TestMigrate_Errors(t *testing.T) {
+	pkgs, err := loader.Load("testdata/p", ".")
+	assert.NoError(t, err)
+
+	_, err = Migrate(pkgs, []string{"User.Age", "Missing"})
+	assert.EqualError(t, err, "no pointer field matches User.Age\nno pointer field matches Missing")
+
+	_, err = Migrate(pkgs, []string{"Pair.A"})
+	assert.ErrorContains(t, err, "p.go:28:2: fields declared together must be migrated together")
+}
+
+func TestApplyEdits(t *testing.T) {
+	out, err := applyEdits([]byte("*x.F"), []edit{
+		{start: 4, end: 4, text: ".Unwrap()"},
+		{start: 0, end: 1},
+		{start: 0, end: 0, text: "v := "},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "v := x.F.Unwrap()", string(out))
+
+	_, err = applyEdits([]byte("*x.F"), []edit{{start: 0, end: 3}, {start: 1, end: 2}})
+	assert.EqualError(t, err, fmt.Sprintf("overlapping edits at offset %d", 1))
+}
@@ -0,0 +1,18 @@
+package p
+
+import (
+	maybe "github.com/shimmerglass/go-optional"
+)
+
+type Post struct {
+	Title  maybe.Option[string]
+	Author User
+}
+
+func (p *Post) AuthorName() string {
+	if p.Author.Name != nil && (*p.Author.Name) != "" {
+		return *p.Author.Name
+	}
+	p.Author.Name = nil
+	return p.Title.TakeOr("anonymous")
+}
@@ -0,0 +1,29 @@
+package p
+
+import "time"
+
+type Address struct {
+	City string
+	Zip  *string
+}
+
+func (a *Address) SetCity(city string) {
+	a.City = city
+}
+
+func (a Address) Label() string {
+	return a.City
+}
+
+type User struct {
+	Name     *string `json:"name"`
+	Nickname *string `json:"nickname,omitempty"`
+	Address  *Address
+	Born     *time.Time
+	Friend   *User
+	Age      int
+}
+
+type Pair struct {
+	A, B *int
+}
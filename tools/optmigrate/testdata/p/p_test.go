@@ -0,0 +1,11 @@
+package p
+
+import "testing"
+
+func TestGreet(t *testing.T) {
+	name := "alice"
+	u := User{&name, nil, &Address{}, nil, nil, 30}
+	if Greet(&u) != "hello alice from , " {
+		t.Fatal(Greet(&u))
+	}
+}
@@ -0,0 +1,5 @@
+package p
+
+func Rename(u *User, opt string) {
+	u.Nickname = &opt
+}
@@ -0,0 +1,35 @@
+package p
+
+import (
+	"fmt"
+	"time"
+)
+
+func NewUser(name string, born time.Time) *User {
+	return &User{Name: &name, Born: &born, Nickname: nil}
+}
+
+func Greet(u *User) string {
+	if u.Name == nil {
+		return "hello"
+	}
+	if nil != u.Nickname {
+		return "hi " + *u.Nickname
+	}
+	return fmt.Sprintf("hello %s from %s, %s", *u.Name, u.Address.City, u.Address.Label())
+}
+
+func Update(u, other *User, zip string) {
+	u.Nickname = nil
+	u.Address = &Address{City: "Paris", Zip: nil}
+	u.Address.City = "Lyon"
+	u.Address.SetCity("Lyon")
+	u.Friend = other.Friend
+	u.Born = lookup()
+	born := u.Born
+	fmt.Println(born)
+}
+
+func lookup() *time.Time {
+	return nil
+}
@@ -0,0 +1,881 @@
+// Package optvet defines an analyzer that reports misuses of opt.Option values.
+package optvet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/shimmerglass/go-optional/internal/opttypes"
+)
+
+const doc = `report misuses of opt.Option values
+
+The optvet analyzer reports:
+
+  - calls to Unwrap, and calls to Take whose error is ignored, on Options that no IsSome or IsNone
+    check guarantees to be Some: they silently give the zero value for None. The suggested fix
+    makes the fallback explicit with TakeOr.
+  - calls to Some with a pointer that may be nil, i.e. that isn't &x, new(T) or known not to be
+    nil. The suggested fix uses PtrFromNillable, which gives None for nil.
+  - nil checks on the value of an Option[*T], which has two empty states: None and Some(nil).
+  - IsSome checks whose body only reads the value with Unwrap. The suggested fix uses IfSome.
+
+An Option is known to be Some in the branches and the statements that an IsSome or IsNone check
+dominates, e.g. after "if o.IsNone() { return }", until it is assigned to. The same goes for the
+pointers checked with "!= nil", and the ones assigned &x or new(T).`
+
+// Analyzer reports misuses of opt.Option values.
+var Analyzer = &analysis.Analyzer{
+	Name: "optvet",
+	Doc:  doc,
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if pass.Pkg.Path() == opttypes.PkgPath {
+		return nil, nil
+	}
+
+	for _, file := range pass.Files {
+		c := &checker{pass: pass, imports: fileImports(pass, file)}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Body != nil {
+					c.walkBlock(decl.Body.List, facts{})
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if vs, ok := spec.(*ast.ValueSpec); ok {
+						for _, v := range vs.Values {
+							c.walkExpr(v, facts{})
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// fileImports returns the names the packages imported by file are known as, by import path.
+func fileImports(pass *analysis.Pass, file *ast.File) map[string]string {
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			imports[path] = spec.Name.Name
+			continue
+		}
+		for _, pkg := range pass.Pkg.Imports() {
+			if pkg.Path() == path {
+				imports[path] = pkg.Name()
+			}
+		}
+	}
+	return imports
+}
+
+// facts is the set of the Options known to be Some, and of the pointers known not to be nil, by key (see checker.key).
+type facts map[string]bool
+
+func (f facts) clone() facts {
+	g := make(facts, len(f))
+	for k := range f {
+		g[k] = true
+	}
+	return g
+}
+
+func (f facts) add(g facts) {
+	for k := range g {
+		f[k] = true
+	}
+}
+
+// invalidate forgets the facts about key and its sub-fields, e.g. after an assignment.
+func (f facts) invalidate(keys ...string) {
+	for _, key := range keys {
+		for k := range f {
+			if covers(key, k) {
+				delete(f, k)
+			}
+		}
+	}
+}
+
+// covers reports whether assigning the variable or field key may change the one at k: k is key or one of its
+// sub-fields. The fields reached through a pointer p are keyed as sub-fields of p, so assigning *p covers them too.
+func covers(key, k string) bool {
+	key = strings.TrimSuffix(key, ".*")
+	return k == key || strings.HasPrefix(k, key+".")
+}
+
+func intersect(a, b facts) facts {
+	f := facts{}
+	for k := range a {
+		if b[k] {
+			f[k] = true
+		}
+	}
+	return f
+}
+
+type checker struct {
+	pass    *analysis.Pass
+	imports map[string]string
+}
+
+func (c *checker) walkBlock(stmts []ast.Stmt, f facts) {
+	for _, s := range stmts {
+		c.walkStmt(s, f)
+	}
+}
+
+// walkStmt checks s, and updates f with what s tells about the Options for the following statements.
+func (c *checker) walkStmt(s ast.Stmt, f facts) {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		c.walkBlock(s.List, f.clone())
+		f.invalidate(c.assignedKeys(s)...)
+
+	case *ast.IfStmt:
+		if s.Init != nil {
+			c.walkStmt(s.Init, f)
+		}
+		c.walkExpr(s.Cond, f)
+		c.checkIfSome(s)
+
+		onTrue, onFalse := c.condFacts(s.Cond)
+		thenFacts := f.clone()
+		thenFacts.add(onTrue)
+		c.walkBlock(s.Body.List, thenFacts)
+		if s.Else != nil {
+			elseFacts := f.clone()
+			elseFacts.add(onFalse)
+			c.walkStmt(s.Else, elseFacts)
+		}
+
+		switch bodyTerm, elseTerm := c.terminates(s.Body), s.Else != nil && c.terminates(s.Else); {
+		case bodyTerm && !elseTerm:
+			f.add(onFalse)
+			if s.Else != nil {
+				f.invalidate(c.assignedKeys(s.Else)...)
+			}
+		case elseTerm && !bodyTerm:
+			f.add(onTrue)
+			f.invalidate(c.assignedKeys(s.Body)...)
+		default:
+			f.invalidate(c.assignedKeys(s)...)
+		}
+
+	case *ast.AssignStmt:
+		for _, e := range s.Rhs {
+			c.walkExpr(e, f)
+		}
+		for _, e := range s.Lhs {
+			c.walkExpr(e, f)
+		}
+		c.checkTake(s.Lhs, s.Rhs, f, s.Tok == token.DEFINE)
+		for _, e := range s.Lhs {
+			if key, ok := c.key(e); ok {
+				f.invalidate(key)
+			}
+		}
+		c.addNonNil(s.Lhs, s.Rhs, f)
+
+	case *ast.DeclStmt:
+		gd, ok := s.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, e := range vs.Values {
+				c.walkExpr(e, f)
+			}
+			lhs := make([]ast.Expr, len(vs.Names))
+			for i, name := range vs.Names {
+				lhs[i] = name
+			}
+			c.checkTake(lhs, vs.Values, f, false)
+			c.addNonNil(lhs, vs.Values, f)
+		}
+
+	case *ast.ExprStmt:
+		c.walkExpr(s.X, f)
+	case *ast.ReturnStmt:
+		for _, e := range s.Results {
+			c.walkExpr(e, f)
+		}
+	case *ast.GoStmt:
+		c.walkExpr(s.Call, f)
+	case *ast.DeferStmt:
+		c.walkExpr(s.Call, f)
+	case *ast.IncDecStmt:
+		c.walkExpr(s.X, f)
+	case *ast.SendStmt:
+		c.walkExpr(s.Chan, f)
+		c.walkExpr(s.Value, f)
+	case *ast.LabeledStmt:
+		c.walkStmt(s.Stmt, f)
+
+	case *ast.ForStmt:
+		f.invalidate(c.assignedKeys(s)...)
+		if s.Init != nil {
+			c.walkStmt(s.Init, f)
+		}
+		body := f.clone()
+		if s.Cond != nil {
+			c.walkExpr(s.Cond, body)
+			onTrue, _ := c.condFacts(s.Cond)
+			body.add(onTrue)
+		}
+		c.walkBlock(s.Body.List, body)
+		if s.Post != nil {
+			c.walkStmt(s.Post, body)
+		}
+
+	case *ast.RangeStmt:
+		c.walkExpr(s.X, f)
+		f.invalidate(c.assignedKeys(s)...)
+		c.walkBlock(s.Body.List, f.clone())
+
+	case *ast.SwitchStmt:
+		if s.Init != nil {
+			c.walkStmt(s.Init, f)
+		}
+		if s.Tag != nil {
+			c.walkExpr(s.Tag, f)
+		}
+		c.walkClauses(s.Body, f, s.Tag == nil)
+		f.invalidate(c.assignedKeys(s)...)
+
+	case *ast.TypeSwitchStmt:
+		if s.Init != nil {
+			c.walkStmt(s.Init, f)
+		}
+		c.walkStmt(s.Assign, f)
+		c.walkClauses(s.Body, f, false)
+		f.invalidate(c.assignedKeys(s)...)
+
+	case *ast.SelectStmt:
+		c.walkClauses(s.Body, f, false)
+		f.invalidate(c.assignedKeys(s)...)
+	}
+}
+
+// walkClauses checks the clauses of a switch or select statement. The cases of a switch without a tag are conditions:
+// a clause knows what its case tells when true, and what the cases evaluated before it tell when false.
+func (c *checker) walkClauses(body *ast.BlockStmt, f facts, conds bool) {
+	// failed holds what is known once every case before the current clause is false, and def when all of them are.
+	failed, def := facts{}, facts{}
+	if conds {
+		for _, clause := range body.List {
+			if clause, ok := clause.(*ast.CaseClause); ok {
+				for _, e := range clause.List {
+					_, onFalse := c.condFacts(e)
+					def.add(onFalse)
+				}
+			}
+		}
+	}
+
+	for _, clause := range body.List {
+		g := f.clone()
+		switch clause := clause.(type) {
+		case *ast.CaseClause:
+			if !conds {
+				for _, e := range clause.List {
+					c.walkExpr(e, g)
+				}
+				c.walkBlock(clause.Body, g)
+				continue
+			}
+			if clause.List == nil {
+				g.add(def)
+				c.walkBlock(clause.Body, g)
+				continue
+			}
+
+			var onTrue facts
+			for i, e := range clause.List {
+				// Each case is only evaluated if the ones before it are false.
+				cond := g.clone()
+				cond.add(failed)
+				c.walkExpr(e, cond)
+				eTrue, eFalse := c.condFacts(e)
+				eTrue.add(failed)
+				if i == 0 {
+					onTrue = eTrue
+				} else {
+					onTrue = intersect(onTrue, eTrue)
+				}
+				failed.add(eFalse)
+			}
+			g.add(onTrue)
+			c.walkBlock(clause.Body, g)
+		case *ast.CommClause:
+			if clause.Comm != nil {
+				c.walkStmt(clause.Comm, g)
+			}
+			c.walkBlock(clause.Body, g)
+		}
+	}
+}
+
+func (c *checker) walkExpr(e ast.Expr, f facts) {
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			c.walkBlock(n.Body.List, f.clone())
+			return false
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c.walkExpr(n.X, f)
+				onTrue, onFalse := c.condFacts(n.X)
+				right := f.clone()
+				if n.Op == token.LAND {
+					right.add(onTrue)
+				} else {
+					right.add(onFalse)
+				}
+				c.walkExpr(n.Y, right)
+				return false
+			}
+			c.checkNilComparison(n)
+		case *ast.UnaryExpr:
+			if key, ok := c.key(n.X); ok && n.Op == token.AND {
+				f.invalidate(key)
+			}
+		case *ast.CallExpr:
+			c.checkCall(n, f)
+		}
+		return true
+	})
+}
+
+// condFacts returns the Options known to be Some when cond is true, and when it's false.
+func (c *checker) condFacts(cond ast.Expr) (facts, facts) {
+	switch e := ast.Unparen(cond).(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			onTrue, onFalse := c.condFacts(e.X)
+			return onFalse, onTrue
+		}
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND:
+			xTrue, xFalse := c.condFacts(e.X)
+			yTrue, yFalse := c.condFacts(e.Y)
+			xTrue.add(yTrue)
+			return xTrue, intersect(xFalse, yFalse)
+		case token.LOR:
+			xTrue, xFalse := c.condFacts(e.X)
+			yTrue, yFalse := c.condFacts(e.Y)
+			xFalse.add(yFalse)
+			return intersect(xTrue, yTrue), xFalse
+		case token.EQL, token.NEQ:
+			key, ok := c.nilCheckedPointer(e)
+			if !ok {
+				break
+			}
+			if e.Op == token.NEQ {
+				return facts{key: true}, facts{}
+			}
+			return facts{}, facts{key: true}
+		}
+	case *ast.CallExpr:
+		for _, name := range []string{"IsSome", "IsNone"} {
+			recv, _, ok := c.optionMethod(e, name)
+			if !ok {
+				continue
+			}
+			key, ok := c.key(recv)
+			if !ok {
+				break
+			}
+			if name == "IsSome" {
+				return facts{key: true}, facts{}
+			}
+			return facts{}, facts{key: true}
+		}
+	}
+	return facts{}, facts{}
+}
+
+// nilCheckedPointer returns the key of the pointer compared to nil by e.
+func (c *checker) nilCheckedPointer(e *ast.BinaryExpr) (string, bool) {
+	value := e.X
+	if c.pass.TypesInfo.Types[e.X].IsNil() {
+		value = e.Y
+	} else if !c.pass.TypesInfo.Types[e.Y].IsNil() {
+		return "", false
+	}
+	tv, ok := c.pass.TypesInfo.Types[value]
+	if !ok {
+		return "", false
+	}
+	if _, ok := tv.Type.Underlying().(*types.Pointer); !ok {
+		return "", false
+	}
+	return c.key(value)
+}
+
+// addNonNil records the pointers of lhs assigned a value of rhs that cannot be nil.
+func (c *checker) addNonNil(lhs, rhs []ast.Expr, f facts) {
+	if len(lhs) != len(rhs) {
+		return
+	}
+	for i, e := range lhs {
+		if key, ok := c.key(e); ok && c.isNonNil(rhs[i]) {
+			f[key] = true
+		}
+	}
+}
+
+// isNonNil reports whether e is a pointer that cannot be nil, &x or new(T).
+func (c *checker) isNonNil(e ast.Expr) bool {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.UnaryExpr:
+		return e.Op == token.AND
+	case *ast.CallExpr:
+		if id, ok := ast.Unparen(e.Fun).(*ast.Ident); ok {
+			b, ok := c.pass.TypesInfo.Uses[id].(*types.Builtin)
+			return ok && b.Name() == "new"
+		}
+	}
+	return false
+}
+
+// key returns a key identifying the variable or field designated by e, if e is a variable, a field selection or a dereference.
+func (c *checker) key(e ast.Expr) (string, bool) {
+	info := c.pass.TypesInfo
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		obj := info.Uses[e]
+		if obj == nil {
+			obj = info.Defs[e]
+		}
+		if _, ok := obj.(*types.Var); !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%p", obj), true
+	case *ast.SelectorExpr:
+		if sel, ok := info.Selections[e]; ok {
+			if sel.Kind() != types.FieldVal {
+				return "", false
+			}
+			key, ok := c.key(e.X)
+			return key + "." + e.Sel.Name, ok
+		}
+		return c.key(e.Sel)
+	case *ast.StarExpr:
+		key, ok := c.key(e.X)
+		return key + ".*", ok
+	}
+	return "", false
+}
+
+// assignedKeys returns the keys of the variables and fields that n may modify.
+func (c *checker) assignedKeys(n ast.Node) []string {
+	var keys []string
+	add := func(e ast.Expr) {
+		if key, ok := c.key(e); ok {
+			keys = append(keys, key)
+		}
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, e := range n.Lhs {
+				add(e)
+			}
+		case *ast.RangeStmt:
+			if n.Key != nil {
+				add(n.Key)
+			}
+			if n.Value != nil {
+				add(n.Value)
+			}
+		case *ast.IncDecStmt:
+			add(n.X)
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				add(n.X)
+			}
+		case *ast.CallExpr:
+			if recv, _, ok := c.optionMethod(n, "Clear"); ok {
+				add(recv)
+			}
+			if recv, _, ok := c.optionMethod(n, "SetAny"); ok {
+				add(recv)
+			}
+		}
+		return true
+	})
+	return keys
+}
+
+// terminates reports whether s never continues to the next statement.
+func (c *checker) terminates(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok != token.FALLTHROUGH
+	case *ast.BlockStmt:
+		return len(s.List) > 0 && c.terminates(s.List[len(s.List)-1])
+	case *ast.IfStmt:
+		return s.Else != nil && c.terminates(s.Body) && c.terminates(s.Else)
+	case *ast.ExprStmt:
+		call, ok := ast.Unparen(s.X).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		b, ok := c.pass.TypesInfo.Uses[id].(*types.Builtin)
+		return ok && b.Name() == "panic"
+	}
+	return false
+}
+
+// optionMethod returns the receiver and the element type of the Option if call is a call to its method name.
+func (c *checker) optionMethod(call *ast.CallExpr, name string) (ast.Expr, types.Type, bool) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return nil, nil, false
+	}
+	selection, ok := c.pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil, nil, false
+	}
+	recv := selection.Recv()
+	if p, ok := recv.(*types.Pointer); ok {
+		recv = p.Elem()
+	}
+	elem, ok := opttypes.OptionElem(recv)
+	if !ok {
+		return nil, nil, false
+	}
+	return sel.X, elem, true
+}
+
+// optFunc returns the expression naming the function if call is a call to the function name of the opt package.
+func (c *checker) optFunc(call *ast.CallExpr, name string) (ast.Expr, bool) {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	var id *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+	default:
+		return nil, false
+	}
+	obj, ok := c.pass.TypesInfo.Uses[id].(*types.Func)
+	if !ok || obj.Pkg() == nil || obj.Pkg().Path() != opttypes.PkgPath || obj.Name() != name {
+		return nil, false
+	}
+	return fun, true
+}
+
+func (c *checker) checkCall(call *ast.CallExpr, f facts) {
+	if recv, elem, ok := c.optionMethod(call, "Unwrap"); ok {
+		if key, ok := c.key(recv); !ok || !f[key] {
+			sel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			c.report(call, fmt.Sprintf("Unwrap called on %s without checking IsSome: it gives the zero value for None", types.ExprString(recv)),
+				c.takeOrFix(sel, call, elem))
+		}
+		return
+	}
+
+	if recv, _, ok := c.optionMethod(call, "Clear"); ok {
+		if key, ok := c.key(recv); ok {
+			f.invalidate(key)
+		}
+		return
+	}
+
+	if fun, ok := c.optFunc(call, "Some"); ok && len(call.Args) == 1 {
+		c.checkSome(call, fun, f)
+	}
+}
+
+// checkTake reports `v, _ := o.Take()` when o isn't known to be Some.
+func (c *checker) checkTake(lhs, rhs []ast.Expr, f facts, define bool) {
+	if len(lhs) != 2 || len(rhs) != 1 || !isBlank(lhs[1]) {
+		return
+	}
+	call, ok := ast.Unparen(rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	recv, elem, ok := c.optionMethod(call, "Take")
+	if !ok {
+		return
+	}
+	if key, ok := c.key(recv); ok && f[key] {
+		return
+	}
+
+	msg := fmt.Sprintf("error of Take ignored on %s without checking IsSome: the value is the zero value for None", types.ExprString(recv))
+	fix := c.takeOrFix(ast.Unparen(call.Fun).(*ast.SelectorExpr), call, elem)
+	if fix == nil || (define && isBlank(lhs[0])) {
+		c.report(call, msg, nil)
+		return
+	}
+	fix.TextEdits = append([]analysis.TextEdit{{Pos: lhs[0].End(), End: lhs[1].End()}}, fix.TextEdits...)
+	c.report(call, msg, fix)
+}
+
+// takeOrFix returns the fix replacing o.Unwrap() or o.Take() with o.TakeOr(zero), if the zero value of elem can be written.
+func (c *checker) takeOrFix(sel *ast.SelectorExpr, call *ast.CallExpr, elem types.Type) *analysis.SuggestedFix {
+	zero, ok := c.zeroValue(elem)
+	if !ok {
+		return nil
+	}
+	return &analysis.SuggestedFix{
+		Message: "Use TakeOr with an explicit fallback",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     sel.Sel.Pos(),
+			End:     call.End(),
+			NewText: []byte("TakeOr(" + zero + ")"),
+		}},
+	}
+}
+
+// checkSome reports calls to Some with nil, or with a pointer that isn't known not to be nil.
+func (c *checker) checkSome(call *ast.CallExpr, fun ast.Expr, f facts) {
+	arg := call.Args[0]
+	tv := c.pass.TypesInfo.Types[arg]
+	if c.isNonNil(arg) {
+		return
+	}
+	if key, ok := c.key(arg); ok && f[key] {
+		return
+	}
+
+	prefix := ""
+	if sel, ok := fun.(*ast.SelectorExpr); ok {
+		prefix = types.ExprString(sel.X) + "."
+	}
+
+	if tv.IsNil() {
+		var fix *analysis.SuggestedFix
+		elem, _ := opttypes.OptionElem(c.pass.TypesInfo.Types[call].Type)
+		if typ, ok := c.typeString(elem); ok {
+			fix = &analysis.SuggestedFix{
+				Message:   "Use None",
+				TextEdits: []analysis.TextEdit{{Pos: call.Pos(), End: call.End(), NewText: []byte(prefix + "None[" + typ + "]()")}},
+			}
+		}
+		c.report(call, "Some called with nil: use None", fix)
+		return
+	}
+	if _, ok := tv.Type.Underlying().(*types.Pointer); !ok {
+		return
+	}
+
+	c.report(call, fmt.Sprintf("Some called with %s, which may be nil: use PtrFromNillable to get None for nil, or FromNillable to get an Option of the pointed value", types.ExprString(arg)),
+		&analysis.SuggestedFix{
+			Message:   "Use PtrFromNillable",
+			TextEdits: []analysis.TextEdit{{Pos: call.Fun.Pos(), End: call.Fun.End(), NewText: []byte(prefix + "PtrFromNillable")}},
+		})
+}
+
+// checkNilComparison reports the nil checks on the value of an Option[*T].
+func (c *checker) checkNilComparison(e *ast.BinaryExpr) {
+	if e.Op != token.EQL && e.Op != token.NEQ {
+		return
+	}
+	value := e.X
+	if c.pass.TypesInfo.Types[e.X].IsNil() {
+		value = e.Y
+	} else if !c.pass.TypesInfo.Types[e.Y].IsNil() {
+		return
+	}
+
+	call, ok := ast.Unparen(value).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	for _, name := range []string{"Unwrap", "TakeOr", "TakeOrElse"} {
+		recv, elem, ok := c.optionMethod(call, name)
+		if !ok {
+			continue
+		}
+		if _, ok := elem.Underlying().(*types.Pointer); ok {
+			c.report(e, fmt.Sprintf("nil check on the value of %s, an Option of a pointer: None and Some(nil) are both empty, use an Option of the pointed type with FromNillable",
+				types.ExprString(recv)), nil)
+		}
+		return
+	}
+}
+
+// checkIfSome reports `if o.IsSome() { ... o.Unwrap() ... }` statements that can be rewritten with IfSome.
+func (c *checker) checkIfSome(s *ast.IfStmt) {
+	if s.Init != nil || s.Else != nil {
+		return
+	}
+	call, ok := ast.Unparen(s.Cond).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	recv, elem, ok := c.optionMethod(call, "IsSome")
+	if !ok {
+		return
+	}
+	key, ok := c.key(recv)
+	if !ok {
+		return
+	}
+	for _, k := range c.assignedKeys(s.Body) {
+		if covers(k, key) {
+			return
+		}
+	}
+
+	// The body moves into a function literal, so it must not return, jump or defer.
+	var unwraps []*ast.CallExpr
+	movable := true
+	var visit func(n ast.Node, inFunc bool)
+	visit = func(n ast.Node, inFunc bool) {
+		ast.Inspect(n, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				visit(n.Body, true)
+				return false
+			case *ast.ReturnStmt, *ast.BranchStmt, *ast.DeferStmt:
+				if !inFunc {
+					movable = false
+				}
+			case *ast.CallExpr:
+				if r, _, ok := c.optionMethod(n, "Unwrap"); ok {
+					if k, ok := c.key(r); ok && k == key {
+						unwraps = append(unwraps, n)
+					}
+				}
+			}
+			return true
+		})
+	}
+	visit(s.Body, false)
+	if !movable || len(unwraps) == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("IsSome check followed by Unwrap: use %s.IfSome", types.ExprString(recv))
+	typ, ok := c.typeString(elem)
+	if !ok {
+		c.report(s, msg, nil)
+		return
+	}
+	name := freshName(s.Body)
+	edits := []analysis.TextEdit{
+		{Pos: s.Pos(), End: s.Body.Lbrace + 1, NewText: []byte(fmt.Sprintf("%s.IfSome(func(%s %s) {", types.ExprString(recv), name, typ))},
+		{Pos: s.Body.Rbrace, End: s.Body.Rbrace + 1, NewText: []byte("})")},
+	}
+	for _, u := range unwraps {
+		edits = append(edits, analysis.TextEdit{Pos: u.Pos(), End: u.End(), NewText: []byte(name)})
+	}
+	c.report(s, msg, &analysis.SuggestedFix{Message: "Use IfSome", TextEdits: edits})
+}
+
+func (c *checker) report(n ast.Node, msg string, fix *analysis.SuggestedFix) {
+	d := analysis.Diagnostic{Pos: n.Pos(), End: n.End(), Message: msg}
+	if fix != nil {
+		d.SuggestedFixes = []analysis.SuggestedFix{*fix}
+	}
+	c.pass.Report(d)
+}
+
+// typeString returns t as written in the current file, or false if it refers to a package the file doesn't import.
+func (c *checker) typeString(t types.Type) (string, bool) {
+	ok := true
+	s := types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == c.pass.Pkg {
+			return ""
+		}
+		name, found := c.imports[pkg.Path()]
+		switch {
+		case !found || name == "_":
+			ok = false
+		case name == ".":
+			return ""
+		}
+		return name
+	})
+	return s, ok
+}
+
+// zeroValue returns the zero value of t as written in the current file.
+func (c *checker) zeroValue(t types.Type) (string, bool) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsNumeric != 0:
+			return "0", true
+		case u.Info()&types.IsString != 0:
+			return `""`, true
+		case u.Info()&types.IsBoolean != 0:
+			return "false", true
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return "nil", true
+	case *types.Struct, *types.Array:
+		if _, ok := types.Unalias(t).(*types.Named); ok {
+			s, ok := c.typeString(t)
+			return s + "{}", ok
+		}
+	}
+	return "", false
+}
+
+// freshName returns a variable name that isn't used in n.
+func freshName(n ast.Node) string {
+	used := map[string]bool{}
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	for _, name := range []string{"v", "value"} {
+		if !used[name] {
+			return name
+		}
+	}
+	for i := 2; ; i++ {
+		if name := "v" + strconv.Itoa(i); !used[name] {
+			return name
+		}
+	}
+}
+
+func isBlank(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "_"
+}
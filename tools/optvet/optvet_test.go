@@ -0,0 +1,13 @@
+package optvet_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/shimmerglass/go-optional/tools/optvet"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), optvet.Analyzer, "a")
+}
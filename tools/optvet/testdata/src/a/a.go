@@ -0,0 +1,115 @@
+package a
+
+import (
+	maybe "github.com/shimmerglass/go-optional"
+)
+
+type User struct {
+	Name    maybe.Option[string]
+	Age     maybe.Option[int]
+	Manager maybe.Option[*User]
+}
+
+func unchecked(u User) int {
+	return u.Age.Unwrap() // want `Unwrap called on u.Age without checking IsSome: it gives the zero value for None`
+}
+
+func checked(u User) int {
+	if u.Age.IsSome() {
+		return u.Age.Unwrap()
+	}
+	if u.Name.IsSome() && u.Name.Unwrap() != "" {
+		return 1
+	}
+	if u.Name.IsNone() || u.Name.Unwrap() == "" {
+		return 0
+	}
+	return len(u.Name.Unwrap())
+}
+
+func earlyReturn(u User) string {
+	if !u.Name.IsSome() {
+		panic("no name")
+	}
+	name := u.Name.Unwrap()
+	u.Name = maybe.None[string]()
+	return name + u.Name.Unwrap() // want `Unwrap called on u.Name without checking`
+}
+
+func elseBranch(u User) int {
+	if u.Age.IsNone() {
+		return 0
+	} else {
+		u.Age.Clear()
+	}
+	return u.Age.Unwrap() // want `Unwrap called on u.Age without checking`
+}
+
+func take(u User) int {
+	age, _ := u.Age.Take() // want `error of Take ignored on u.Age without checking IsSome`
+	if u.Age.IsNone() {
+		return age
+	}
+	checkedAge, _ := u.Age.Take()
+	return checkedAge
+}
+
+func lookup() *User {
+	return nil
+}
+
+func some(u *User) {
+	_ = maybe.Some(u) // want `Some called with u, which may be nil: use PtrFromNillable`
+	_ = maybe.Some(&User{})
+	_ = maybe.Some(new(User))
+	if u != nil {
+		_ = maybe.Some(u)
+	}
+	p := &User{}
+	_ = maybe.Some(p)
+	p = lookup()
+	_ = maybe.Some(p) // want `Some called with p, which may be nil`
+	if p == nil {
+		return
+	}
+	_ = maybe.Some(p)
+	_ = maybe.Some[*User](nil) // want `Some called with nil: use None`
+}
+
+func nilCheck(u User) bool {
+	return u.Manager.Unwrap() != nil // want `Unwrap called on u.Manager` `nil check on the value of u.Manager, an Option of a pointer`
+}
+
+func ifSome(u User) {
+	if u.Name.IsSome() { // want `IsSome check followed by Unwrap: use u.Name.IfSome`
+		println(u.Name.Unwrap())
+	}
+	if u.Name.IsSome() {
+		return
+	}
+	if u.Age.IsSome() { // want `IsSome check followed by Unwrap: use u.Age.IfSome`
+		v := 1
+		println(u.Age.Unwrap() + v)
+	}
+}
+
+func ifSomeReassigned(u, other User) {
+	if u.Name.IsSome() {
+		println(u.Name.Unwrap())
+		u = other
+		println(u.Name.Unwrap()) // want `Unwrap called on u.Name without checking`
+	}
+}
+
+func switchCases(u User) int {
+	switch {
+	case u.Age.IsSome():
+		return u.Age.Unwrap()
+	case u.Name.IsNone(), u.Name.Unwrap() == "":
+		return 0
+	case u.Age.IsNone() && u.Name.IsSome():
+		return len(u.Name.Unwrap())
+	default:
+		return len(u.Name.Unwrap()) + u.Age.Unwrap() // want `Unwrap called on u.Age without checking`
+	}
+}
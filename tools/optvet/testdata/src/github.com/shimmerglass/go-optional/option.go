@@ -0,0 +1,48 @@
+// Package opt is a stub of the opt package for the optvet tests.
+package opt
+
+type Option[T any] struct {
+	value  T
+	isSome bool
+}
+
+func Some[T any](v T) Option[T] { return Option[T]{value: v, isSome: true} }
+
+func None[T any]() Option[T] { return Option[T]{} }
+
+func FromNillable[T any](v *T) Option[T] {
+	if v == nil {
+		return None[T]()
+	}
+	return Some(*v)
+}
+
+func PtrFromNillable[T any](v *T) Option[*T] {
+	if v == nil {
+		return None[*T]()
+	}
+	return Some(v)
+}
+
+func (o Option[T]) IsSome() bool { return o.isSome }
+
+func (o Option[T]) IsNone() bool { return !o.isSome }
+
+func (o Option[T]) Unwrap() T { return o.value }
+
+func (o Option[T]) Take() (T, error) { return o.value, nil }
+
+func (o Option[T]) TakeOr(fallback T) T {
+	if !o.isSome {
+		return fallback
+	}
+	return o.value
+}
+
+func (o Option[T]) IfSome(f func(v T)) {
+	if o.isSome {
+		f(o.value)
+	}
+}
+
+func (o *Option[T]) Clear() { *o = None[T]() }
@@ -0,0 +1,150 @@
+package opt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RequiredError represents a required field that is None, or the zero value for a non-Option field.
+// It wraps ErrNoneValueTaken, so that errors.Is(err, ErrNoneValueTaken) holds.
+type RequiredError struct {
+	// Path is the dotted path of the field, made of the names of its `yaml` or `json` tags, or of its field names, e.g. "spec.tls.cert".
+	Path string
+	// Rule is the rule that requires the field, e.g. "required" or "required_if=Mode:tls".
+	Rule string
+}
+
+func (e *RequiredError) Error() string {
+	return fmt.Sprintf("%s: required value is missing (%s)", e.Path, e.Rule)
+}
+
+func (e *RequiredError) Unwrap() error {
+	return ErrNoneValueTaken
+}
+
+// Validate walks v, which can be a struct, a slice, a map or a pointer to one of them, and checks the `opt` tags of struct fields.
+// The tag holds comma-separated rules:
+//
+//   - `required`: the field must be Some, or must not be the zero value if it's not an Option.
+//   - `required_if=Field:value`: the field is required if the sibling field Field is set to value, compared with its fmt representation.
+//   - `required_with=Field`: the field is required if the sibling field Field is Some, or is not the zero value if it's not an Option.
+//
+// Nested structs, pointers, slices, arrays, maps and Some values of Options are walked recursively.
+// This returns every missing field as a *RequiredError, joined together with errors.Join.
+func Validate(v any) error {
+	var errs []error
+	validateValue(reflect.ValueOf(v), "", &errs)
+	return errors.Join(errs...)
+}
+
+func validateValue(v reflect.Value, path string, errs *[]error) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			validateValue(v.Elem(), path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), errs)
+		}
+	case reflect.Struct:
+		if o, ok := v.Interface().(Optional); ok {
+			if value, ok := o.AnyValue(); ok {
+				validateValue(reflect.ValueOf(value), path, errs)
+			}
+			return
+		}
+		validateStruct(v, path, errs)
+	}
+}
+
+func validateStruct(v reflect.Value, path string, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fieldPath := configFieldName(f)
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		fv := v.Field(i)
+
+		if tag := f.Tag.Get("opt"); tag != "" {
+			for _, rule := range strings.Split(tag, ",") {
+				required, err := isRequired(v, strings.TrimSpace(rule))
+				if err != nil {
+					*errs = append(*errs, fmt.Errorf("%s: %w", fieldPath, err))
+					continue
+				}
+				if required && !isSet(fv) {
+					*errs = append(*errs, &RequiredError{Path: fieldPath, Rule: strings.TrimSpace(rule)})
+					break
+				}
+			}
+		}
+
+		validateValue(fv, fieldPath, errs)
+	}
+}
+
+// isRequired reports whether the rule requires its field to be set, given the struct holding the field.
+func isRequired(parent reflect.Value, rule string) (bool, error) {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		return true, nil
+	case "required_if":
+		field, expected, ok := strings.Cut(arg, ":")
+		if !ok {
+			return false, fmt.Errorf("invalid rule %q, expected required_if=Field:value", rule)
+		}
+		sibling, err := siblingField(parent, field)
+		if err != nil {
+			return false, err
+		}
+		value, ok := setValue(sibling)
+		return ok && fmt.Sprint(value) == expected, nil
+	case "required_with":
+		sibling, err := siblingField(parent, arg)
+		if err != nil {
+			return false, err
+		}
+		return isSet(sibling), nil
+	default:
+		return false, fmt.Errorf("unknown rule %q", rule)
+	}
+}
+
+func siblingField(parent reflect.Value, name string) (reflect.Value, error) {
+	f := parent.FieldByName(name)
+	if !f.IsValid() {
+		return f, fmt.Errorf("unknown field %s in %s", name, parent.Type())
+	}
+	return f, nil
+}
+
+// setValue returns the value of v, unwrapped if it's an Option, and whether it's set, i.e. Some or not the zero value.
+func setValue(v reflect.Value) (any, bool) {
+	if !v.CanInterface() {
+		return nil, false
+	}
+	if o, ok := v.Interface().(Optional); ok {
+		return o.AnyValue()
+	}
+	return v.Interface(), !v.IsZero()
+}
+
+func isSet(v reflect.Value) bool {
+	_, ok := setValue(v)
+	return ok
+}
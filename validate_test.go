@@ -0,0 +1,83 @@
+package opt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validateTLS struct {
+	Mode Option[string] `json:"mode"`
+	Cert Option[string] `json:"cert" opt:"required_if=Mode:tls"`
+	Key  Option[string] `json:"key" opt:"required_with=Cert"`
+}
+
+type validateSpec struct {
+	Name    Option[string]          `json:"name" opt:"required"`
+	Owner   string                  `json:"owner" opt:"required"`
+	TLS     validateTLS             `json:"tls"`
+	Backups []validateTLS           `json:"backups"`
+	Peers   map[string]*validateTLS `json:"peers"`
+	Extra   Option[validateTLS]     `json:"extra"`
+}
+
+type validateResource struct {
+	Spec validateSpec `json:"spec"`
+}
+
+func TestValidate(t *testing.T) {
+	valid := validateResource{Spec: validateSpec{
+		Name:  Some("foo"),
+		Owner: "me",
+		TLS:   validateTLS{Mode: Some("tls"), Cert: Some("cert.pem"), Key: Some("key.pem")},
+	}}
+	assert.NoError(t, Validate(valid))
+	assert.NoError(t, Validate(&valid))
+	assert.NoError(t, Validate([]validateResource{valid}))
+
+	invalid := validateResource{Spec: validateSpec{
+		TLS:     validateTLS{Mode: Some("tls")},
+		Backups: []validateTLS{{Mode: Some("plain")}, {Cert: Some("cert.pem")}},
+		Peers:   map[string]*validateTLS{"a": {Mode: Some("tls"), Key: Some("key.pem")}},
+		Extra:   Some(validateTLS{Cert: Some("x")}),
+	}}
+	err := Validate(&invalid)
+	assert.ErrorIs(t, err, ErrNoneValueTaken)
+	assert.EqualError(t, err, `spec.name: required value is missing (required)
+spec.owner: required value is missing (required)
+spec.tls.cert: required value is missing (required_if=Mode:tls)
+spec.backups[1].key: required value is missing (required_with=Cert)
+spec.peers[a].cert: required value is missing (required_if=Mode:tls)
+spec.extra.key: required value is missing (required_with=Cert)`)
+
+	var joined interface{ Unwrap() []error }
+	assert.True(t, errors.As(err, &joined))
+	for _, e := range joined.Unwrap() {
+		assert.ErrorIs(t, e, ErrNoneValueTaken)
+	}
+
+	var required *RequiredError
+	assert.True(t, errors.As(err, &required))
+	assert.Equal(t, &RequiredError{Path: "spec.name", Rule: "required"}, required)
+}
+
+func TestValidate_InvalidRules(t *testing.T) {
+	type Invalid struct {
+		A Option[int] `opt:"required_if=B"`
+		B Option[int] `opt:"required_with=D"`
+		C Option[int] `opt:"mandatory"`
+	}
+
+	err := Validate(Invalid{})
+	assert.EqualError(t, err, `A: invalid rule "required_if=B", expected required_if=Field:value
+B: unknown field D in opt.Invalid
+C: unknown rule "mandatory"`)
+	assert.NotErrorIs(t, err, ErrNoneValueTaken)
+}
+
+func TestValidate_NonStruct(t *testing.T) {
+	assert.NoError(t, Validate(nil))
+	assert.NoError(t, Validate(42))
+	assert.NoError(t, Validate(map[string]int{"a": 1}))
+}